@@ -0,0 +1,359 @@
+package main
+
+import (
+    "fmt"
+    "path"
+    "strconv"
+    "strings"
+)
+
+// Versions the CI jobs set up; Go follows backend/go.mod on GitHub
+const (
+    ciNodeVersion   = "22"
+    ciPythonVersion = "3.12"
+    ciGoImage       = "golang:1"
+)
+
+// The database password in CI, where the service only lives as long as the job
+const ciDatabasePassword = "ci-password"
+
+// ciProvider is a CI service the ci recipe can write config for
+type ciProvider struct {
+    name  string
+    label string
+    path  string
+}
+
+// In the same order as ciChoices
+var ciProviders = []ciProvider{
+    {name: "github", label: "GitHub Actions", path: ".github/workflows/ci.yml"},
+    {name: "gitlab", label: "GitLab CI", path: ".gitlab-ci.yml"},
+}
+
+// ciJob is one CI job: the toolchain it sets up (node, go or python), the
+// directory its commands run in, and the commands
+type ciJob struct {
+    name      string
+    toolchain string
+    dir       string
+    steps     []ciStep
+    // database gives the job the database service and DATABASE_URL
+    database bool
+}
+
+type ciStep struct {
+    name string
+    run  string
+    // env is set for this step only
+    env [][2]string
+}
+
+// ciRecipe writes a CI config that installs, typechecks, builds and tests
+// whatever the other recipes scaffolded
+type ciRecipe struct{}
+
+func (r *ciRecipe) Name() string           { return "ci" }
+func (r *ciRecipe) Description() string    { return "Write a GitHub Actions or GitLab CI config" }
+func (r *ciRecipe) Dependencies() []string { return []string{"directories"} }
+
+func (r *ciRecipe) Prompt(p *project) (bool, error) {
+    items := make([]string, 0, len(ciProviders)+1)
+    for _, provider := range ciProviders {
+        items = append(items, provider.label)
+    }
+    items = append(items, "None")
+
+    choice, answered := presetChoice(opts.ci, "github", ciChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your CI:", items, choice, answered))
+    if n > len(ciProviders) {
+        fmt.Printf("%sSkipping CI setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.ci = ciProviders[n-1]
+    return true, nil
+}
+
+func (r *ciRecipe) Apply(p *project) error {
+    // CI and developers run the same typecheck script
+    if p.applied["vite"] {
+        if err := editPackageJSON("frontend", func(pkg *packageJSON) error {
+            pkg.setScript("typecheck", p.framework.typecheck)
+            return nil
+        }); err != nil {
+            return err
+        }
+    }
+    if p.applied["backend"] && p.server.language == "typescript" {
+        if err := editPackageJSON("backend", func(pkg *packageJSON) error {
+            pkg.setScript("typecheck", "tsc --noEmit")
+            return nil
+        }); err != nil {
+            return err
+        }
+    }
+
+    jobs := p.ciJobs()
+    if len(jobs) == 0 {
+        fmt.Printf("%sNo app was scaffolded, so there is nothing for CI to check.%s\n", ColorYellow, ColorReset)
+        return errSkipped
+    }
+    var config string
+    switch p.ci.name {
+    case "gitlab":
+        config = p.gitlabPipeline(jobs)
+    default:
+        config = p.githubWorkflow(jobs)
+    }
+    if err := ensureDirectory(path.Dir(p.ci.path)); err != nil {
+        return err
+    }
+    if err := writeFile(p.ci.path, []byte(config), 0644); err != nil {
+        return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, p.ci.path, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! %s config written to %s.%s\n", ColorGreen, p.ci.label, p.ci.path, ColorReset)
+    return nil
+}
+
+// One job per app, or a single job at the root for a workspace, which
+// installs once for every package
+func (p *project) ciJobs() []ciJob {
+    var nodeApps []string
+    if p.applied["vite"] {
+        nodeApps = append(nodeApps, "frontend")
+    }
+    if p.applied["backend"] && p.server.language == "typescript" {
+        nodeApps = append(nodeApps, "backend")
+    }
+    install := ciStep{name: "Install dependencies", run: p.pm.ciInstallCommand(p.pmVersion)}
+
+    var jobs []ciJob
+    if p.applied["workspace"] {
+        job := ciJob{name: "build", toolchain: "node", dir: ".", steps: []ciStep{install}, database: contains(nodeApps, "backend")}
+        if p.applied["shared"] {
+            job.steps = append(job.steps, ciStep{name: "Build " + p.sharedPackageName(), run: p.pm.workspaceRunCommand(p.sharedPackageName(), "build")})
+        }
+        for _, app := range nodeApps {
+            job.steps = append(job.steps, p.ciAppSteps(app, true)...)
+        }
+        jobs = append(jobs, job)
+    } else {
+        for _, app := range nodeApps {
+            steps := append([]ciStep{install}, p.ciAppSteps(app, false)...)
+            jobs = append(jobs, ciJob{name: app, toolchain: "node", dir: app, steps: steps, database: app == "backend"})
+        }
+    }
+
+    if p.applied["backend"] {
+        switch p.server.language {
+        case "go":
+            jobs = append(jobs, ciJob{name: "backend", toolchain: "go", dir: "backend", database: true, steps: []ciStep{
+                {name: "Vet", run: "go vet ./..."},
+                {name: "Build", run: "go build ./..."},
+                {name: "Test", run: "go test ./...", env: [][2]string{{"APP_ENV", "test"}}},
+            }})
+        case "python":
+            steps := []ciStep{
+                {name: "Install dependencies", run: "python -m pip install -e ."},
+                {name: "Check that the app imports", run: `python -c "import app.main"`, env: [][2]string{{"APP_ENV", "test"}}},
+            }
+            if pathExistsQuiet("backend/tests") {
+                steps = append(steps,
+                    ciStep{name: "Install pytest", run: "python -m pip install pytest"},
+                    ciStep{name: "Test", run: "python -m pytest", env: [][2]string{{"APP_ENV", "test"}}})
+            }
+            jobs = append(jobs, ciJob{name: "backend", toolchain: "python", dir: "backend", database: true, steps: steps})
+        }
+    }
+    return jobs
+}
+
+// Typecheck, build and test one Node app, from the workspace root or the app's directory
+func (p *project) ciAppSteps(app string, workspace bool) []ciStep {
+    run := func(script string) string {
+        if workspace {
+            return p.pm.workspaceRunCommand(app, script)
+        }
+        return p.pm.name + " run " + script
+    }
+    var steps []ciStep
+    // The backend does not typecheck until the Prisma client is generated
+    if app == "backend" && p.applied["orm"] && p.orm.name == "prisma" {
+        steps = append(steps, ciStep{name: "Generate the Prisma client", run: run("db:generate")})
+    }
+    steps = append(steps,
+        ciStep{name: "Typecheck " + app, run: run("typecheck")},
+        ciStep{name: "Build " + app, run: run("build")})
+    if hasTestScript(app) {
+        steps = append(steps, ciStep{name: "Test " + app, run: run("test"), env: [][2]string{{"NODE_ENV", "test"}}})
+    }
+    return steps
+}
+
+// Whether an app's package.json has a test script, not counting the
+// placeholder npm init writes, which always fails
+func hasTestScript(dir string) bool {
+    if opts.dryRun {
+        return false
+    }
+    pkg, err := loadPackageJSON(dir + "/package.json")
+    if err != nil {
+        return false
+    }
+    test, ok := pkg.root.object("scripts").get("test")
+    return ok && !strings.Contains(fmt.Sprint(test), "no test specified")
+}
+
+// The service the database job runs next to, reachable as host, and the
+// DATABASE_URL pointing at it. SQLite needs no service, and MongoDB gets none:
+// Prisma needs it to run as a replica set, which a CI service cannot initiate
+func (p *project) ciDatabase(host string) (*composeService, string) {
+    if !p.applied["database"] {
+        return nil, ""
+    }
+    // The same test database DATABASE_URL in backend/.env.test points at
+    dbName := p.templateData().DBName + "_test"
+    switch p.database.name {
+    case "sqlite":
+        return nil, "file:./test.db"
+    case "mongodb":
+        return nil, ""
+    }
+    service := p.database.composeService(p.database.containerPort, dbName)
+    service.environment = p.database.containerEnv(dbName, ciDatabasePassword)
+    service.envFile = nil
+    if host == "" {
+        host = service.name
+    }
+    return &service, p.database.hostURL(host, p.database.containerPort, dbName, ciDatabasePassword)
+}
+
+// The workflow for GitHub Actions, run on pushes to main and on pull requests
+func (p *project) githubWorkflow(jobs []ciJob) string {
+    var b strings.Builder
+    b.WriteString("name: CI\n\non:\n  push:\n    branches: [main]\n  pull_request:\n\njobs:\n")
+    for i, job := range jobs {
+        if i > 0 {
+            b.WriteString("\n")
+        }
+        fmt.Fprintf(&b, "  %s:\n    runs-on: ubuntu-latest\n", job.name)
+        if job.database {
+            // Services publish their ports on the runner, where the steps run
+            service, url := p.ciDatabase("localhost")
+            if service != nil {
+                fmt.Fprintf(&b, "    services:\n      %s:\n        image: %s\n", service.name, yamlValue(service.image))
+                b.WriteString("        env:\n")
+                for _, kv := range service.environment {
+                    fmt.Fprintf(&b, "          %s: %s\n", kv[0], yamlValue(kv[1]))
+                }
+                fmt.Fprintf(&b, "        ports:\n          - %s\n", yamlValue(fmt.Sprintf("%d:%d", p.database.containerPort, p.database.containerPort)))
+                if h := service.healthcheck; h != nil {
+                    // $$ only escapes $ for compose; docker runs the command as is
+                    fmt.Fprintf(&b, "        options: >-\n          --health-cmd %s\n          --health-interval %s\n          --health-timeout %s\n          --health-retries %d\n",
+                        strconv.Quote(strings.ReplaceAll(h.test, "$$", "$")), h.interval, h.timeout, h.retries)
+                }
+            }
+            if url != "" {
+                fmt.Fprintf(&b, "    env:\n      DATABASE_URL: %s\n", yamlValue(url))
+            }
+        }
+        if job.dir != "." {
+            fmt.Fprintf(&b, "    defaults:\n      run:\n        working-directory: %s\n", job.dir)
+        }
+        b.WriteString("    steps:\n      - uses: actions/checkout@v4\n")
+        b.WriteString(p.githubSetupSteps(job))
+        for _, step := range job.steps {
+            fmt.Fprintf(&b, "      - name: %s\n        run: %s\n", yamlCommand(step.name), yamlCommand(step.run))
+            if len(step.env) > 0 {
+                b.WriteString("        env:\n")
+                for _, kv := range step.env {
+                    fmt.Fprintf(&b, "          %s: %s\n", kv[0], yamlValue(kv[1]))
+                }
+            }
+        }
+    }
+    return b.String()
+}
+
+// The steps that install a job's toolchain, with the package manager's
+// dependency cache where setup-node supports it
+func (p *project) githubSetupSteps(job ciJob) string {
+    switch job.toolchain {
+    case "go":
+        return fmt.Sprintf("      - uses: actions/setup-go@v5\n        with:\n          go-version-file: %s/go.mod\n", job.dir)
+    case "python":
+        return fmt.Sprintf("      - uses: actions/setup-python@v5\n        with:\n          python-version: %s\n", yamlValue(ciPythonVersion))
+    }
+
+    var b strings.Builder
+    lockfile := path.Join(job.dir, p.pm.lockfile)
+    switch p.pm.name {
+    case "bun":
+        version := p.pmVersion
+        if version == "" {
+            version = "latest"
+        }
+        fmt.Fprintf(&b, "      - uses: oven-sh/setup-bun@v2\n        with:\n          bun-version: %s\n", yamlValue(version))
+        return b.String()
+    case "pnpm":
+        // pnpm/action-setup reads the version from the packageManager field
+        if p.pmVersion != "" {
+            fmt.Fprintf(&b, "      - uses: pnpm/action-setup@v4\n        with:\n          package_json_file: %s\n", path.Join(job.dir, "package.json"))
+        } else {
+            b.WriteString("      - uses: pnpm/action-setup@v4\n        with:\n          version: latest\n")
+        }
+    }
+    fmt.Fprintf(&b, "      - uses: actions/setup-node@v4\n        with:\n          node-version: %s\n", yamlValue(ciNodeVersion))
+    if p.pm.name == "yarn" {
+        // Yarn comes from corepack, which setup-node cannot cache before it is enabled
+        b.WriteString("      - run: corepack enable\n")
+    } else {
+        fmt.Fprintf(&b, "          cache: %s\n          cache-dependency-path: %s\n", p.pm.name, lockfile)
+    }
+    return b.String()
+}
+
+// The pipeline for GitLab CI: one stage, with each job in its toolchain's image
+func (p *project) gitlabPipeline(jobs []ciJob) string {
+    var b strings.Builder
+    b.WriteString("stages:\n  - check\n")
+    for _, job := range jobs {
+        image := "node:" + ciNodeVersion
+        switch {
+        case job.toolchain == "go":
+            image = ciGoImage
+        case job.toolchain == "python":
+            image = "python:" + ciPythonVersion
+        case p.pm.name == "bun":
+            image = "oven/bun:1"
+        }
+        fmt.Fprintf(&b, "\n%s:\n  stage: check\n  image: %s\n", job.name, yamlValue(image))
+        if job.database {
+            // Services are reachable under their alias
+            service, url := p.ciDatabase("")
+            if service != nil {
+                fmt.Fprintf(&b, "  services:\n    - name: %s\n      alias: %s\n      variables:\n", yamlValue(service.image), service.name)
+                for _, kv := range service.environment {
+                    fmt.Fprintf(&b, "        %s: %s\n", kv[0], yamlValue(kv[1]))
+                }
+            }
+            if url != "" {
+                fmt.Fprintf(&b, "  variables:\n    DATABASE_URL: %s\n", yamlValue(url))
+            }
+        }
+        b.WriteString("  script:\n")
+        if job.dir != "." {
+            fmt.Fprintf(&b, "    - cd %s\n", job.dir)
+        }
+        if job.toolchain == "node" && (p.pm.name == "pnpm" || p.pm.name == "yarn") {
+            b.WriteString("    - corepack enable\n")
+        }
+        for _, step := range job.steps {
+            command := step.run
+            for i := len(step.env) - 1; i >= 0; i-- {
+                command = step.env[i][0] + "=" + step.env[i][1] + " " + command
+            }
+            fmt.Fprintf(&b, "    - %s\n", yamlCommand(command))
+        }
+    }
+    return b.String()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// The compose file every recipe adds its services to, at the project root
+const composePath = "docker-compose.yml"
+
+// composeFile is the project's docker-compose.yml. Recipes add services with
+// p.addComposeService, which rewrites the whole file from these types
+type composeFile struct {
+    services []composeService
+}
+
+// composeService is one entry under services, running either image or what
+// build builds. Volumes whose source is a plain name (not a path) are declared
+// as named volumes at the top level
+type composeService struct {
+    name        string
+    image       string
+    build       *composeBuild
+    command     string
+    environment [][2]string
+    envFile     []string
+    ports       []string
+    volumes     []string
+    healthcheck *composeHealthcheck
+    // dependsOn waits for each service to be healthy, or started when it has no healthcheck
+    dependsOn []string
+}
+
+// composeBuild builds a service's image from a Dockerfile in context, or from
+// dockerfile, relative to context, when set
+type composeBuild struct {
+    context    string
+    dockerfile string
+}
+
+type composeHealthcheck struct {
+    // test is run by the container's shell (CMD-SHELL)
+    test        string
+    interval    string
+    timeout     string
+    startPeriod string
+    retries     int
+}
+
+func (c composeFile) clone() composeFile {
+    return composeFile{services: append([]composeService(nil), c.services...)}
+}
+
+func (c composeFile) service(name string) (composeService, bool) {
+    for _, s := range c.services {
+        if s.name == name {
+            return s, true
+        }
+    }
+    return composeService{}, false
+}
+
+// Add a service to the shared compose file, replacing one with the same name,
+// and write the file
+func (p *project) addComposeService(s composeService) error {
+    replaced := false
+    for i, existing := range p.compose.services {
+        if existing.name == s.name {
+            p.compose.services[i] = s
+            replaced = true
+        }
+    }
+    if !replaced {
+        p.compose.services = append(p.compose.services, s)
+    }
+    if err := writeFile(composePath, []byte(p.compose.yaml()), 0644); err != nil {
+        return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, composePath, err, ColorReset)
+    }
+    return nil
+}
+
+// Serialize the file as compose YAML, without the obsolete version key
+func (c composeFile) yaml() string {
+    var b strings.Builder
+    var namedVolumes []string
+    b.WriteString("services:\n")
+    for i, s := range c.services {
+        if i > 0 {
+            b.WriteString("\n")
+        }
+        fmt.Fprintf(&b, "  %s:\n", s.name)
+        if s.build != nil && s.build.dockerfile != "" {
+            fmt.Fprintf(&b, "    build:\n      context: %s\n      dockerfile: %s\n", yamlValue(s.build.context), yamlValue(s.build.dockerfile))
+        } else if s.build != nil {
+            fmt.Fprintf(&b, "    build: %s\n", yamlValue(s.build.context))
+        }
+        if s.image != "" {
+            fmt.Fprintf(&b, "    image: %s\n", yamlValue(s.image))
+        }
+        if s.command != "" {
+            fmt.Fprintf(&b, "    command: %s\n", yamlValue(s.command))
+        }
+        if len(s.environment) > 0 {
+            b.WriteString("    environment:\n")
+            for _, kv := range s.environment {
+                fmt.Fprintf(&b, "      %s: %s\n", kv[0], yamlValue(kv[1]))
+            }
+        }
+        writeYAMLList(&b, "env_file", s.envFile)
+        writeYAMLList(&b, "ports", s.ports)
+        writeYAMLList(&b, "volumes", s.volumes)
+        for _, volume := range s.volumes {
+            source, _, _ := strings.Cut(volume, ":")
+            if !strings.ContainsAny(source, "./~$") && !contains(namedVolumes, source) {
+                namedVolumes = append(namedVolumes, source)
+            }
+        }
+        if h := s.healthcheck; h != nil {
+            b.WriteString("    healthcheck:\n")
+            fmt.Fprintf(&b, "      test: [\"CMD-SHELL\", %s]\n", quoteYAML(h.test))
+            for _, kv := range [][2]string{{"interval", h.interval}, {"timeout", h.timeout}, {"start_period", h.startPeriod}} {
+                if kv[1] != "" {
+                    fmt.Fprintf(&b, "      %s: %s\n", kv[0], kv[1])
+                }
+            }
+            if h.retries > 0 {
+                fmt.Fprintf(&b, "      retries: %d\n", h.retries)
+            }
+        }
+        if len(s.dependsOn) > 0 {
+            b.WriteString("    depends_on:\n")
+            for _, dep := range s.dependsOn {
+                condition := "service_started"
+                if target, ok := c.service(dep); ok && target.healthcheck != nil {
+                    condition = "service_healthy"
+                }
+                fmt.Fprintf(&b, "      %s:\n        condition: %s\n", dep, condition)
+            }
+        }
+    }
+    if len(namedVolumes) > 0 {
+        b.WriteString("\nvolumes:\n")
+        for _, name := range namedVolumes {
+            fmt.Fprintf(&b, "  %s:\n", name)
+        }
+    }
+    return b.String()
+}
+
+func writeYAMLList(b *strings.Builder, key string, items []string) {
+    if len(items) == 0 {
+        return
+    }
+    fmt.Fprintf(b, "    %s:\n", key)
+    for _, item := range items {
+        fmt.Fprintf(b, "      - %s\n", yamlValue(item))
+    }
+}
+
+var plainYAML = regexp.MustCompile(`^[A-Za-z_./][A-Za-z0-9_./-]*$`)
+
+// Write a string plain when YAML cannot mistake it for anything else, and
+// quoted otherwise (ports like 5432:5432 would read as base-60 numbers)
+func yamlValue(s string) string {
+    switch strings.ToLower(s) {
+    case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+        return quoteYAML(s)
+    }
+    if _, err := strconv.ParseFloat(s, 64); err != nil && plainYAML.MatchString(s) {
+        return s
+    }
+    return quoteYAML(s)
+}
+
+// A JSON string is a valid double-quoted YAML scalar
+func quoteYAML(s string) string {
+    quoted, _ := json.Marshal(s)
+    return string(quoted)
+}
+
+// Write a shell command or other free text plain when YAML reads it back
+// unchanged, which covers most commands, and quoted otherwise
+func yamlCommand(s string) string {
+    if s == "" || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") || strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+        return quoteYAML(s)
+    }
+    // With a space in it, it cannot read as a number, boolean or null either
+    if strings.Contains(s, " ") {
+        return s
+    }
+    return yamlValue(s)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// The env file the api service reads DATABASE_URL and the session secret from,
+// next to docker-compose.yml. Its database host is the compose service, not localhost
+const apiEnvPath = ".env.api"
+
+// dockerRecipe writes a multi-stage Dockerfile and a .dockerignore for each
+// app, and can add api and web services that run them to docker-compose.yml
+type dockerRecipe struct{}
+
+func (r *dockerRecipe) Name() string           { return "docker" }
+func (r *dockerRecipe) Description() string    { return "Write Dockerfiles and compose services" }
+func (r *dockerRecipe) Dependencies() []string { return []string{"directories"} }
+
+func (r *dockerRecipe) Prompt(p *project) (bool, error) {
+    if !getYesNoResponse("Do you want Dockerfiles for the apps", opts.docker) {
+        fmt.Printf("%sSkipping Dockerfiles.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.composeApps = getYesNoResponse("Do you want docker-compose.yml to build and run the apps too", opts.dockerCompose)
+    return true, nil
+}
+
+func (r *dockerRecipe) Apply(p *project) error {
+    if !p.applied["vite"] && !p.applied["backend"] {
+        fmt.Printf("%sNo app was scaffolded, so there is nothing to containerize.%s\n", ColorYellow, ColorReset)
+        return errSkipped
+    }
+    fmt.Printf("%sWriting Dockerfiles...%s\n", ColorBlue, ColorReset)
+
+    // The templates test what was scaffolded, like addAppServices, not what was
+    // selected: an app whose recipe was skipped or failed gets no Dockerfile
+    data := p.templateData()
+    for name := range data.Features {
+        data.Features[name] = p.applied[name]
+    }
+    // In a workspace the images build from the root, where the lockfile and
+    // shared package are, so the root gets the .dockerignore
+    if err := renderTemplates("docker", data); err != nil {
+        return err
+    }
+    if p.composeApps {
+        if err := p.addAppServices(); err != nil {
+            return err
+        }
+        fmt.Printf("%sJeez! Run 'docker compose up --build' to start the whole stack.%s\n", ColorGreen, ColorReset)
+        return nil
+    }
+    fmt.Printf("%sJeez! Dockerfiles written.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Add the api service, built from backend/ and talking to the database
+// service, and the web service, built from frontend/ and served by nginx. Both
+// publish the ports the dev servers use, so the frontend finds the API where it
+// expects it
+func (p *project) addAppServices() error {
+    build := func(app string) *composeBuild {
+        if p.applied["workspace"] {
+            return &composeBuild{context: ".", dockerfile: app + "/Dockerfile"}
+        }
+        return &composeBuild{context: "./" + app}
+    }
+
+    if p.applied["backend"] {
+        api := composeService{
+            name:        "api",
+            build:       build("backend"),
+            environment: [][2]string{{"PORT", strconv.Itoa(p.backendPort)}},
+            envFile:     []string{apiEnvPath},
+            ports:       []string{fmt.Sprintf("%d:%d", p.backendPort, p.backendPort)},
+        }
+        lines := []string{"SESSION_SECRET=" + p.sessionSecret}
+        if p.applied["database"] {
+            data := p.templateData()
+            db := p.database.composeService(data.DBPort, data.DBName)
+            lines = append([]string{"DATABASE_URL=" + p.database.hostURL(db.name, p.database.containerPort, data.DBName, p.dbPassword)}, lines...)
+            // SQLite's file is inside the api container, with no service to wait for
+            if p.database.name != "sqlite" {
+                api.dependsOn = []string{db.name}
+            }
+        }
+        if err := ignoreEnvFiles(); err != nil {
+            return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+        }
+        if err := writeFile(apiEnvPath, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+            return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, apiEnvPath, err, ColorReset)
+        }
+        if err := p.addComposeService(api); err != nil {
+            return err
+        }
+    }
+
+    if p.applied["vite"] {
+        web := composeService{
+            name:  "web",
+            build: build("frontend"),
+            ports: []string{fmt.Sprintf("%d:80", p.frontendPort)},
+        }
+        if p.applied["backend"] {
+            web.dependsOn = []string{"api"}
+        }
+        if err := p.addComposeService(web); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Each app directory gets layered env files. The runtimes load .env.local
+// (or .env.test under NODE_ENV=test / APP_ENV=test) and then .env, and the
+// first file to set a variable wins:
+//
+//   - .env: the settings jeez generated; the Prisma CLI reads it too
+//   - .env.local: this machine's overrides, from the spec's env values
+//   - .env.test: a separate database and secret for tests
+//   - .env.example: every variable with placeholders, the only one committed
+//
+// The frontend gets its own files holding only VITE_* variables, because Vite
+// bundles those into code that runs in the browser
+
+// envVar is one KEY=value line; example is what .env.example shows instead
+type envVar struct {
+    key     string
+    value   string
+    example string
+}
+
+// What .gitignore needs so no env file but .env.example is committed
+var envIgnorePatterns = []string{".env", ".env.*", "!.env.example"}
+
+// envRecipe writes the env files and keeps them out of Git
+type envRecipe struct{}
+
+func (r *envRecipe) Name() string           { return "env" }
+func (r *envRecipe) Description() string    { return "Write .env files, .env.example and .gitignore" }
+func (r *envRecipe) Dependencies() []string { return []string{"directories"} }
+
+func (r *envRecipe) Prompt(p *project) (bool, error) {
+    _, frontendValues := splitEnvValues(opts.envValues)
+    if !p.backend && len(frontendValues) == 0 {
+        return false, nil
+    }
+    if !getYesNoResponse("Do you want to create .env files for the database and secrets", opts.env) {
+        fmt.Printf("%sSkipping .env setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    return true, nil
+}
+
+func (r *envRecipe) Apply(p *project) error {
+    if err := ignoreEnvFiles(); err != nil {
+        return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+    }
+    backendValues, frontendValues := splitEnvValues(opts.envValues)
+
+    if p.backend {
+        data := p.templateData()
+        port := strconv.Itoa(data.BackendPort)
+        generated := []envVar{
+            {"PORT", port, port},
+            {"SESSION_SECRET", p.sessionSecret, "change-me"},
+        }
+
+        // Tests get their own database and secret
+        testSecret, err := randomSecret(32)
+        if err != nil {
+            return err
+        }
+        test := []envVar{{key: "SESSION_SECRET", value: testSecret}}
+
+        // Point DATABASE_URL at the database set up above, if there is one
+        if p.applied["database"] {
+            url := envVar{"DATABASE_URL", p.database.url(data.DBPort, data.DBName, p.dbPassword), p.database.url(data.DBPort, data.DBName, "change-me")}
+            testURL := p.database.url(data.DBPort, data.DBName+"_test", p.dbPassword)
+            if p.database.name == "sqlite" {
+                testURL = "file:./test.db"
+            }
+            generated = append([]envVar{url}, generated...)
+            test = append([]envVar{{key: "DATABASE_URL", value: testURL}}, test...)
+        }
+
+        if err := writeEnvFiles("backend", generated, backendValues, test); err != nil {
+            return err
+        }
+    }
+
+    if p.selected["vite"] {
+        var generated []envVar
+        if p.backend {
+            url := fmt.Sprintf("http://localhost:%d", p.backendPort)
+            generated = append(generated, envVar{"VITE_API_URL", url, url})
+        }
+        if len(generated) > 0 || len(frontendValues) > 0 {
+            if err := writeEnvFiles("frontend", generated, frontendValues, nil); err != nil {
+                return err
+            }
+        }
+    }
+
+    fmt.Printf("%sJeez! .env files created, with .env.example to commit.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Write dir/.env with the generated settings, dir/.env.local with the values
+// the user gave, dir/.env.test when test values are given, and dir/.env.example
+func writeEnvFiles(dir string, generated []envVar, local []envVar, test []envVar) error {
+    example := append([]envVar(nil), generated...)
+    for _, v := range local {
+        if !containsEnvKey(example, v.key) {
+            example = append(example, v)
+        }
+    }
+
+    files := []struct {
+        name string
+        vars []envVar
+    }{
+        {".env", generated},
+        {".env.local", local},
+        {".env.test", test},
+    }
+    for _, file := range files {
+        if len(file.vars) == 0 {
+            continue
+        }
+        path := dir + "/" + file.name
+        if err := writeFile(path, []byte(formatEnvFile(file.vars, false)), 0600); err != nil {
+            return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, path, err, ColorReset)
+        }
+    }
+    path := dir + "/.env.example"
+    if err := writeFile(path, []byte(formatEnvFile(example, true)), 0644); err != nil {
+        return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, path, err, ColorReset)
+    }
+    return nil
+}
+
+func formatEnvFile(vars []envVar, example bool) string {
+    var b strings.Builder
+    if example {
+        b.WriteString("# Copy to .env.local and fill in; jeez wrote this project's values to .env\n")
+    }
+    for _, v := range vars {
+        value := v.value
+        if example {
+            value = v.example
+        }
+        b.WriteString(formatEnvLine(v.key, value) + "\n")
+    }
+    return b.String()
+}
+
+// Quote values dotenv would otherwise cut at a # or trim
+func formatEnvLine(key string, value string) string {
+    if !strings.ContainsAny(value, "#\"'\n") && strings.TrimSpace(value) == value {
+        return key + "=" + value
+    }
+    if !strings.ContainsAny(value, "'\n") {
+        return key + "='" + value + "'"
+    }
+    return key + "=" + strconv.Quote(value)
+}
+
+func containsEnvKey(vars []envVar, key string) bool {
+    for _, v := range vars {
+        if v.key == key {
+            return true
+        }
+    }
+    return false
+}
+
+// Split extra values into the backend's and the frontend's VITE_* ones, sorted
+// by key. Their .env.example entries are left blank
+func splitEnvValues(values map[string]string) (backend []envVar, frontend []envVar) {
+    for _, key := range mapKeys(values) {
+        v := envVar{key: key, value: values[key]}
+        if strings.HasPrefix(key, "VITE_") {
+            frontend = append(frontend, v)
+        } else {
+            backend = append(backend, v)
+        }
+    }
+    return backend, frontend
+}
+
+// Add the env patterns to the project's .gitignore, keeping what is there
+func ignoreEnvFiles() error {
+    return addToGitignore("Env files hold secrets; .env.example lists the variables", envIgnorePatterns)
+}
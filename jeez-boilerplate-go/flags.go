@@ -0,0 +1,276 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "strconv"
+)
+
+// optionalBool is a boolean flag that remembers whether it was given at all,
+// so an unset flag can fall back to an interactive prompt
+type optionalBool struct {
+    set   bool
+    value bool
+}
+
+func (b *optionalBool) String() string {
+    if !b.set {
+        return ""
+    }
+    return strconv.FormatBool(b.value)
+}
+
+func (b *optionalBool) Set(s string) error {
+    v, err := strconv.ParseBool(s)
+    if err != nil {
+        return err
+    }
+    b.set = true
+    b.value = v
+    return nil
+}
+
+func (b *optionalBool) IsBoolFlag() bool { return true }
+
+// Options collected from the command line. Every prompt checks here first
+// and is skipped when the matching flag was given
+type options struct {
+    name          string
+    layout        string
+    frontend      string
+    backend       string
+    db            string
+    orm           string
+    remote        string
+    pm            string
+    monorepo      string
+    ci            string
+    git           optionalBool
+    tailwind      optionalBool
+    storybook     optionalBool
+    env           optionalBool
+    shared        optionalBool
+    docker        optionalBool
+    dockerCompose optionalBool
+    envValues     map[string]string
+    models        []prismaModel
+    modelsFile    string
+    specFile      string
+    yes           bool
+    dryRun        bool
+    onFailure     string
+    listRecipes   bool
+    skipPreflight bool
+    showSecrets   bool
+}
+
+var opts options
+
+// Allowed values for the menu flags
+var (
+    layoutChoices   = map[string]string{"frontend": "1", "backend": "2", "fullstack": "3"}
+    frontendChoices = map[string]string{"react": "1", "vue": "2", "svelte": "3", "solid": "4", "vanilla": "5", "skip": "6", "vite": "1"}
+    backendChoices  = map[string]string{"express": "1", "fastify": "2", "hono": "3", "nestjs": "4", "go": "5", "fastapi": "6", "skip": "7"}
+    dbChoices       = map[string]string{"postgres": "1", "mysql": "2", "sqlite": "3", "mongodb": "4", "none": "5"}
+    ormChoices      = map[string]string{"prisma": "1", "drizzle": "2", "typeorm": "3", "kysely": "4", "none": "5"}
+    monorepoChoices = map[string]string{"workspaces": "1", "turbo": "2", "nx": "3", "none": "4"}
+    ciChoices       = map[string]string{"github": "1", "gitlab": "2", "none": "3"}
+)
+
+// Parse command-line flags into opts and validate them. "jeez new -f jeez.yaml"
+// takes every answer from a spec file instead of prompting
+func parseFlags(args []string) error {
+    command := "jeez"
+    if len(args) > 0 && args[0] == "new" {
+        command = "jeez new"
+        args = args[1:]
+    }
+
+    fs := flag.NewFlagSet(command, flag.ContinueOnError)
+    fs.StringVar(&opts.specFile, "file", "", "project spec file (jeez.yaml or jeez.json); no prompts are shown")
+    fs.StringVar(&opts.specFile, "f", "", "shorthand for --file")
+    fs.StringVar(&opts.name, "name", "", "project name")
+    fs.StringVar(&opts.layout, "layout", "", "project layout: frontend, backend or fullstack")
+    fs.StringVar(&opts.frontend, "frontend", "", "frontend framework: react, vue, svelte, solid, vanilla or skip")
+    fs.StringVar(&opts.backend, "backend", "", "backend framework: express, fastify, hono, nestjs, go, fastapi or skip")
+    fs.StringVar(&opts.db, "db", "", "database: postgres, mysql, sqlite, mongodb or none")
+    fs.StringVar(&opts.orm, "orm", "", "ORM: prisma, drizzle, typeorm, kysely or none")
+    fs.StringVar(&opts.remote, "remote", "", "remote Git repository URL, or skip")
+    fs.StringVar(&opts.pm, "pm", "", "package manager for every Node package: npm, pnpm, yarn or bun")
+    fs.StringVar(&opts.monorepo, "monorepo", "", "link frontend and backend in a root workspace: workspaces, turbo, nx or none")
+    fs.StringVar(&opts.ci, "ci", "", "CI config to write: github, gitlab or none")
+    fs.StringVar(&opts.modelsFile, "models-file", "", "YAML or JSON file listing Prisma models under models:, as a spec file does (implies --orm prisma)")
+    bun := optionalBool{}
+    fs.Var(&bun, "bun", "shorthand for --pm bun")
+    fs.Var(&opts.git, "git", "initialize a Git repository")
+    fs.Var(&opts.tailwind, "tailwind", "install TailwindCSS in the frontend")
+    fs.Var(&opts.storybook, "storybook", "install Storybook in the frontend")
+    fs.Var(&opts.shared, "shared", "add packages/shared with zod schemas for both apps (needs --monorepo)")
+    fs.Var(&opts.docker, "docker", "write a Dockerfile and .dockerignore for each app")
+    fs.Var(&opts.dockerCompose, "docker-compose", "add api and web services that build the apps to docker-compose.yml (needs --docker)")
+    fs.Var(&opts.env, "env", "write .env files with the database URL and secrets")
+    fs.BoolVar(&opts.yes, "yes", false, "accept the default answer for every remaining prompt")
+    fs.BoolVar(&opts.yes, "y", false, "shorthand for --yes")
+    fs.StringVar(&opts.onFailure, "on-failure", "", "what to do when a step fails: rollback, keep or abort (default: ask, or rollback with --yes)")
+    fs.BoolVar(&opts.listRecipes, "list-recipes", false, "list every recipe with its dependencies and exit")
+    fs.BoolVar(&opts.skipPreflight, "skip-preflight", false, "do not check for required tools before starting")
+    fs.BoolVar(&opts.dryRun, "dry-run", false, "print every command, file write and directory change without doing any of them")
+    fs.BoolVar(&opts.showSecrets, "show-secrets", false, "print the generated database password and session secret at the end")
+    if err := fs.Parse(args); err != nil {
+        return err
+    }
+    if bun.set && bun.value && opts.pm == "" {
+        opts.pm = "bun"
+    }
+    if fs.NArg() > 0 {
+        return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+    }
+    if opts.modelsFile != "" {
+        models, err := loadModelsFile(opts.modelsFile)
+        if err != nil {
+            return err
+        }
+        opts.models = models
+    }
+    if opts.specFile != "" {
+        spec, err := loadSpec(opts.specFile)
+        if err != nil {
+            return err
+        }
+        applySpec(spec)
+    }
+    if opts.modelsFile != "" && opts.orm == "" {
+        opts.orm = "prisma"
+    }
+    return validateOptions()
+}
+
+// Check flag values up front so a non-interactive run fails before creating anything
+func validateOptions() error {
+    if opts.layout != "" {
+        if _, ok := layoutChoices[opts.layout]; !ok {
+            return fmt.Errorf("invalid --layout %q (want frontend, backend or fullstack)", opts.layout)
+        }
+    }
+    if opts.frontend != "" {
+        if _, ok := frontendChoices[opts.frontend]; !ok {
+            return fmt.Errorf("invalid --frontend %q (want react, vue, svelte, solid, vanilla or skip)", opts.frontend)
+        }
+    }
+    if opts.frontend == "solid" && opts.storybook.set && opts.storybook.value {
+        return fmt.Errorf("--storybook is not supported with --frontend solid")
+    }
+    if opts.backend != "" {
+        if _, ok := backendChoices[opts.backend]; !ok {
+            return fmt.Errorf("invalid --backend %q (want express, fastify, hono, nestjs, go, fastapi or skip)", opts.backend)
+        }
+    }
+    if (opts.backend == "go" || opts.backend == "fastapi") && opts.orm != "" && opts.orm != "none" {
+        return fmt.Errorf("--orm %s needs a TypeScript backend, not --backend %s", opts.orm, opts.backend)
+    }
+    if opts.db == "mongodb" && opts.orm != "" && opts.orm != "prisma" && opts.orm != "none" {
+        return fmt.Errorf("--orm %s does not support --db mongodb (use prisma)", opts.orm)
+    }
+    if opts.pm != "" && !contains(packageManagerNames(), opts.pm) {
+        return fmt.Errorf("invalid --pm %q (want npm, pnpm, yarn or bun)", opts.pm)
+    }
+    if opts.monorepo != "" {
+        if _, ok := monorepoChoices[opts.monorepo]; !ok {
+            return fmt.Errorf("invalid --monorepo %q (want workspaces, turbo, nx or none)", opts.monorepo)
+        }
+        if opts.monorepo != "none" && (opts.layout == "frontend" || opts.layout == "backend") {
+            return fmt.Errorf("--monorepo %s needs --layout fullstack", opts.monorepo)
+        }
+        if opts.monorepo != "none" && (opts.backend == "go" || opts.backend == "fastapi") {
+            return fmt.Errorf("--monorepo %s needs a TypeScript backend, not --backend %s", opts.monorepo, opts.backend)
+        }
+    }
+    if opts.shared.set && opts.shared.value && opts.monorepo == "none" {
+        return fmt.Errorf("--shared needs a workspace, not --monorepo none")
+    }
+    if opts.dockerCompose.set && opts.dockerCompose.value && opts.docker.set && !opts.docker.value {
+        return fmt.Errorf("--docker-compose needs --docker")
+    }
+    if opts.ci != "" {
+        if _, ok := ciChoices[opts.ci]; !ok {
+            return fmt.Errorf("invalid --ci %q (want github, gitlab or none)", opts.ci)
+        }
+    }
+    if opts.db != "" {
+        if _, ok := dbChoices[opts.db]; !ok {
+            return fmt.Errorf("invalid --db %q (want postgres, mysql, sqlite, mongodb or none)", opts.db)
+        }
+    }
+    if opts.orm != "" {
+        if _, ok := ormChoices[opts.orm]; !ok {
+            return fmt.Errorf("invalid --orm %q (want prisma, drizzle, typeorm, kysely or none)", opts.orm)
+        }
+    }
+    if opts.modelsFile != "" {
+        if opts.orm != "prisma" {
+            return fmt.Errorf("--models-file needs --orm prisma, not --orm %s", opts.orm)
+        }
+        for _, engine := range databaseEngines {
+            if engine.name == opts.db {
+                if err := checkPrismaProvider(opts.models, engine.prismaProvider); err != nil {
+                    return fmt.Errorf("%s: %w", opts.modelsFile, err)
+                }
+            }
+        }
+    }
+    if opts.onFailure != "" && !contains([]string{"rollback", "keep", "abort"}, opts.onFailure) {
+        return fmt.Errorf("invalid --on-failure %q (want rollback, keep or abort)", opts.onFailure)
+    }
+    if opts.remote != "" && opts.remote != "skip" && !isValidURL(opts.remote) {
+        return fmt.Errorf("invalid --remote %q (want a URL or skip)", opts.remote)
+    }
+    if opts.name != "" {
+        if _, err := os.Stat(opts.name); !os.IsNotExist(err) {
+            return fmt.Errorf("a directory with the name '%s' already exists", opts.name)
+        }
+    }
+    if opts.yes && opts.name == "" {
+        return fmt.Errorf("--name is required with --yes")
+    }
+    return nil
+}
+
+// Answer a yes/no question from a flag or --yes, if possible
+func presetYesNo(answer optionalBool) (bool, bool) {
+    if answer.set {
+        return answer.value, true
+    }
+    if opts.yes {
+        return true, true
+    }
+    return false, false
+}
+
+// Answer a numbered menu from a flag or --yes, if possible
+func presetChoice(value string, def string, choices map[string]string) (string, bool) {
+    if value != "" {
+        return choices[value], true
+    }
+    if opts.yes {
+        return choices[def], true
+    }
+    return "", false
+}
+
+// Turn a name-valued flag (like --db) into a yes/no answer
+func flagToggle(value string, off string) optionalBool {
+    if value == "" {
+        return optionalBool{}
+    }
+    return optionalBool{set: true, value: value != off}
+}
+
+func contains(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
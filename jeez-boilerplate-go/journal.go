@@ -0,0 +1,245 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// errSkipped is returned by a step when the user chose not to run it
+var errSkipped = errors.New("skipped")
+
+// Files larger than this are not backed up before a step; they are still
+// removed on rollback if the step created them
+const maxBackupSize = 1 << 20
+
+// Directories whose contents are treated as a single unit: if one exists before
+// a step it is left alone, if a step creates it the whole tree is removed
+var opaqueDirs = map[string]bool{"node_modules": true, ".git": true, ".venv": true, "__pycache__": true}
+
+type stepStatus int
+
+const (
+    stepSucceeded stepStatus = iota
+    stepSkipped
+    stepFailed
+    stepRolledBack
+)
+
+// stepRecord is what the final summary reports for one step
+type stepRecord struct {
+    name    string
+    status  stepStatus
+    err     error
+    reason  string
+    created []string
+    // blocked marks a skipped step that was wanted but could not run: its
+    // tools were missing, or a step it needs was blocked or failed
+    blocked bool
+}
+
+// snapshot is the state of the project tree before a step ran
+type snapshot struct {
+    paths map[string]fs.FileMode
+    files map[string][]byte
+}
+
+var (
+    projectRoot string
+    stepRecords []stepRecord
+)
+
+// Record the paths under root, and the contents of small files, so a step can be undone
+func takeSnapshot(root string) (*snapshot, error) {
+    snap := &snapshot{paths: map[string]fs.FileMode{}, files: map[string][]byte{}}
+    err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        rel, _ := filepath.Rel(root, path)
+        if rel == "." {
+            return nil
+        }
+        info, err := d.Info()
+        if err != nil {
+            return err
+        }
+        snap.paths[rel] = info.Mode()
+        if d.IsDir() && opaqueDirs[d.Name()] {
+            return filepath.SkipDir
+        }
+        if info.Mode().IsRegular() && info.Size() <= maxBackupSize {
+            data, err := os.ReadFile(path)
+            if err != nil {
+                return err
+            }
+            snap.files[rel] = data
+        }
+        return nil
+    })
+    // A partial snapshot would make restore delete whatever the walk missed
+    if err != nil {
+        return nil, err
+    }
+    return snap, nil
+}
+
+// Paths that exist now but did not in the snapshot. Only the topmost new
+// path is listed, so a new directory hides everything inside it
+func (s *snapshot) created(root string) []string {
+    var created []string
+    filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+        if err != nil {
+            return nil
+        }
+        rel, _ := filepath.Rel(root, path)
+        if rel == "." {
+            return nil
+        }
+        if _, existed := s.paths[rel]; !existed {
+            created = append(created, rel)
+            if d.IsDir() {
+                return filepath.SkipDir
+            }
+            return nil
+        }
+        if d.IsDir() && opaqueDirs[d.Name()] {
+            return filepath.SkipDir
+        }
+        return nil
+    })
+    return created
+}
+
+// Put the project tree back the way it was when the snapshot was taken
+func (s *snapshot) restore(root string) error {
+    for _, rel := range s.created(root) {
+        if err := os.RemoveAll(filepath.Join(root, rel)); err != nil {
+            return fmt.Errorf("%sfailed to remove %s: %w%s", ColorRed, rel, err, ColorReset)
+        }
+    }
+
+    rels := make([]string, 0, len(s.paths))
+    for rel := range s.paths {
+        rels = append(rels, rel)
+    }
+    sort.Strings(rels) // parents before children
+
+    for _, rel := range rels {
+        mode := s.paths[rel]
+        path := filepath.Join(root, rel)
+        if mode.IsDir() {
+            if err := os.MkdirAll(path, mode.Perm()); err != nil {
+                return fmt.Errorf("%sfailed to restore %s: %w%s", ColorRed, rel, err, ColorReset)
+            }
+            continue
+        }
+        data, backedUp := s.files[rel]
+        if !backedUp {
+            continue
+        }
+        if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+            continue
+        }
+        if err := os.WriteFile(path, data, mode.Perm()); err != nil {
+            return fmt.Errorf("%sfailed to restore %s: %w%s", ColorRed, rel, err, ColorReset)
+        }
+    }
+    return nil
+}
+
+// Ask what to do about a failed recipe; returns its final status
+func handleStepFailure(p *project, r Recipe, snap *snapshot, status stepStatus) stepStatus {
+    name := r.Name()
+    choice := opts.onFailure
+    if choice == "" && opts.yes {
+        choice = "rollback"
+    }
+    reader := bufio.NewReader(os.Stdin)
+    for choice == "" {
+        fmt.Printf("%sThe %s recipe failed. What do you want to do?%s\n", ColorBold, name, ColorReset)
+        fmt.Println("1. Roll back this recipe and continue")
+        fmt.Println("2. Keep what it created and continue")
+        fmt.Println("3. Delete the whole project and exit")
+        fmt.Printf("%sEnter your choice (1/2/3): %s", ColorYellow, ColorReset)
+        input, _ := reader.ReadString('\n')
+        switch strings.TrimSpace(input) {
+        case "1", "":
+            choice = "rollback"
+        case "2":
+            choice = "keep"
+        case "3":
+            choice = "abort"
+        default:
+            fmt.Printf("%sInvalid choice. Please select 1, 2, or 3.%s\n", ColorRed, ColorReset)
+        }
+    }
+
+    switch choice {
+    case "rollback":
+        if rb, ok := r.(RollbackRecipe); ok {
+            if err := rb.Rollback(p); err != nil {
+                handleError("rolling back "+name, err)
+            }
+        }
+        if err := snap.restore(projectRoot); err != nil {
+            handleError("rolling back "+name, err)
+            return status
+        }
+        fmt.Printf("%sRolled back the %s recipe.%s\n", ColorYellow, name, ColorReset)
+        return stepRolledBack
+    case "abort":
+        deleteProject()
+    }
+    return status
+}
+
+// Remove the project directory and exit
+func deleteProject() {
+    if err := os.RemoveAll(projectRoot); err != nil {
+        handleError("deleting the project", err)
+        os.Exit(1)
+    }
+    fmt.Printf("%sDeleted %s.%s\n", ColorYellow, projectRoot, ColorReset)
+    os.Exit(1)
+}
+
+// Print what succeeded, what was skipped and what failed. Returns false if
+// anything failed, or a wanted step could not run
+func printSummary() bool {
+    ok := true
+    fmt.Printf("%sSummary:%s\n", ColorBold, ColorReset)
+    for _, record := range stepRecords {
+        switch record.status {
+        case stepSucceeded:
+            fmt.Printf("  %s✔ %s%s", ColorGreen, record.name, ColorReset)
+            if len(record.created) > 0 {
+                fmt.Printf(" (created %s)", strings.Join(record.created, ", "))
+            }
+            fmt.Println()
+        case stepSkipped:
+            ok = ok && !record.blocked
+            reason := "skipped"
+            if record.reason != "" {
+                reason += ": " + record.reason
+            }
+            fmt.Printf("  %s- %s (%s)%s\n", ColorYellow, record.name, reason, ColorReset)
+        case stepFailed:
+            ok = false
+            fmt.Printf("  %s✘ %s: %v%s\n", ColorRed, record.name, record.err, ColorReset)
+            if len(record.created) > 0 {
+                fmt.Printf("    left behind: %s\n", strings.Join(record.created, ", "))
+            }
+        case stepRolledBack:
+            ok = false
+            fmt.Printf("  %s✘ %s: %v (rolled back)%s\n", ColorRed, record.name, record.err, ColorReset)
+        }
+    }
+    return ok
+}
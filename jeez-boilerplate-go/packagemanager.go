@@ -0,0 +1,244 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// packageManager knows how to spell each command for npm, pnpm, yarn or bun
+type packageManager struct {
+    name     string
+    lockfile string
+}
+
+var packageManagers = []packageManager{
+    {name: "npm", lockfile: "package-lock.json"},
+    {name: "pnpm", lockfile: "pnpm-lock.yaml"},
+    {name: "yarn", lockfile: "yarn.lock"},
+    {name: "bun", lockfile: "bun.lock"},
+}
+
+func packageManagerNames() []string {
+    names := make([]string, len(packageManagers))
+    for i, pm := range packageManagers {
+        names[i] = pm.name
+    }
+    return names
+}
+
+// Look up a package manager by name, defaulting to npm
+func packageManagerFor(name string) packageManager {
+    for _, pm := range packageManagers {
+        if pm.name == name {
+            return pm
+        }
+    }
+    return packageManagers[0]
+}
+
+// Tools a recipe needs to run this package manager's commands
+func (pm packageManager) tools() []string {
+    switch pm.name {
+    case "npm":
+        return []string{"node", "npm", "npx"}
+    case "bun":
+        return []string{"bun"}
+    }
+    return []string{"node", pm.name}
+}
+
+func (pm packageManager) initCommand() []string {
+    if pm.name == "pnpm" {
+        return []string{"pnpm", "init"}
+    }
+    return []string{pm.name, "init", "-y"}
+}
+
+func (pm packageManager) installCommand() []string {
+    return []string{pm.name, "install"}
+}
+
+// Install exactly what the lockfile says, failing if it is out of date, as CI
+// should. Yarn 1 spells the flag differently from Yarn 2 and later
+func (pm packageManager) ciInstallCommand(version string) string {
+    switch pm.name {
+    case "npm":
+        return "npm ci"
+    case "yarn":
+        if strings.HasPrefix(version, "1.") {
+            return "yarn install --frozen-lockfile"
+        }
+        return "yarn install --immutable"
+    }
+    return pm.name + " install --frozen-lockfile"
+}
+
+// Drop the dev dependencies from an installed node_modules, as a Docker image
+// should before it ships. Bun can only reinstall without them
+func (pm packageManager) pruneCommand(version string) string {
+    switch pm.name {
+    case "npm":
+        return "npm prune --omit=dev"
+    case "pnpm":
+        return "pnpm prune --prod"
+    case "yarn":
+        if strings.HasPrefix(version, "1.") {
+            return "yarn install --production --frozen-lockfile"
+        }
+        return "yarn workspaces focus --all --production"
+    }
+    return "rm -rf node_modules && bun install --production --frozen-lockfile"
+}
+
+func (pm packageManager) addCommand(packages ...string) []string {
+    if pm.name == "npm" {
+        return append([]string{"npm", "install"}, packages...)
+    }
+    return append([]string{pm.name, "add"}, packages...)
+}
+
+func (pm packageManager) addDevCommand(packages ...string) []string {
+    if pm.name == "npm" {
+        return append([]string{"npm", "install", "-D"}, packages...)
+    }
+    return append([]string{pm.name, "add", "-D"}, packages...)
+}
+
+// Add dev dependencies to the root package.json of a workspace; pnpm refuses
+// to without -w
+func (pm packageManager) addRootDevCommand(packages ...string) []string {
+    if pm.name == "pnpm" {
+        return append([]string{"pnpm", "add", "-D", "-w"}, packages...)
+    }
+    return pm.addDevCommand(packages...)
+}
+
+// The shell command that runs one workspace package's script from the root
+func (pm packageManager) workspaceRunCommand(workspace string, script string) string {
+    switch pm.name {
+    case "npm":
+        return fmt.Sprintf("npm run %s -w %s", script, workspace)
+    case "pnpm":
+        return fmt.Sprintf("pnpm --filter %s run %s", workspace, script)
+    case "yarn":
+        return fmt.Sprintf("yarn workspace %s run %s", workspace, script)
+    }
+    return fmt.Sprintf("bun run --filter %s %s", workspace, script)
+}
+
+// Run a package's binary, downloading it if needed (npx / pnpm dlx / yarn dlx / bunx)
+func (pm packageManager) execCommand(args ...string) []string {
+    switch pm.name {
+    case "npm":
+        return append([]string{"npx"}, args...)
+    case "bun":
+        return append([]string{"bunx"}, args...)
+    }
+    return append([]string{pm.name, "dlx"}, args...)
+}
+
+// Run a create-* starter, passing extra flags through to it
+func (pm packageManager) createCommand(starter string, args ...string) []string {
+    switch pm.name {
+    case "npm":
+        if len(args) > 1 {
+            args = append(args[:1:1], append([]string{"--"}, args[1:]...)...)
+        }
+        return append([]string{"npm", "create", starter}, args...)
+    case "bun":
+        return append([]string{"bunx", "create-" + starter}, args...)
+    }
+    return append([]string{pm.name, "create", starter}, args...)
+}
+
+// Helper function to run a package manager command in a project-relative directory
+func runPackageManager(dir string, command []string) error {
+    return runCommand(dir, command[0], command[1:]...)
+}
+
+// Make sure dir has this package manager's lockfile and no other. Some
+// starters run npm on their own, which would leave a second lockfile behind
+func (p *project) ensureLockfile(dir string) error {
+    for _, other := range packageManagers {
+        if other.name == p.pm.name || !pathExistsQuiet(dir+"/"+other.lockfile) {
+            continue
+        }
+        if opts.dryRun {
+            recordPlan("remove", dir+"/"+other.lockfile, "")
+            continue
+        }
+        if err := os.Remove(projectPath(dir + "/" + other.lockfile)); err != nil {
+            return fmt.Errorf("%sfailed to remove %s: %w%s", ColorRed, other.lockfile, err, ColorReset)
+        }
+    }
+    if pathExistsQuiet(dir + "/" + p.pm.lockfile) {
+        return nil
+    }
+    return runPackageManager(dir, p.pm.installCommand())
+}
+
+// Record the package manager in package.json so tools like corepack pick the same one
+func (p *project) setPackageManagerField(pkg *packageJSON) {
+    if p.pmVersion != "" {
+        pkg.setField("packageManager", p.pm.name+"@"+p.pmVersion)
+    }
+}
+
+// packageManagerRecipe chooses the package manager every Node recipe uses
+type packageManagerRecipe struct{}
+
+func (r *packageManagerRecipe) Name() string           { return "packagemanager" }
+func (r *packageManagerRecipe) Description() string    { return "Choose the Node package manager" }
+func (r *packageManagerRecipe) Dependencies() []string { return nil }
+
+func (r *packageManagerRecipe) RequiredTools() []string {
+    if !flagsAllowFrontend() && !flagsAllowNodeBackend() {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *packageManagerRecipe) Prompt(p *project) (bool, error) {
+    // Nothing left to run needs Node, as with --layout backend --backend go
+    if !flagsAllowFrontend() && !flagsAllowNodeBackend() {
+        return false, nil
+    }
+    names := packageManagerNames()
+    choices := map[string]string{}
+    for i, name := range names {
+        choices[name] = fmt.Sprint(i + 1)
+    }
+    for {
+        choice, answered := presetChoice(opts.pm, "npm", choices)
+        n, _ := strconv.Atoi(chooseFromMenu("Select your package manager:", names, choice, answered))
+        pm := packageManagers[n-1]
+
+        // A dry run runs no process, so the version stays unknown
+        if opts.dryRun {
+            recordPlan("assume", pm.name+" is installed (version unknown)", "")
+            p.pm = pm
+            return true, nil
+        }
+        // A choice from a flag or spec was already checked by preflight, which
+        // decides what to do when it is missing
+        status := checkTool(pm.name)
+        if status.problem != "" && !answered {
+            fmt.Printf("%s%s: %s. Please choose another one.%s\n", ColorRed, pm.name, status.problem, ColorReset)
+            continue
+        }
+        p.pm = pm
+        p.pmVersion = status.version
+        return true, nil
+    }
+}
+
+func (r *packageManagerRecipe) Apply(p *project) error {
+    name := p.pm.name
+    if p.pmVersion != "" {
+        name += " " + p.pmVersion
+    }
+    fmt.Printf("%sJeez! Using %s for every package.%s\n", ColorGreen, name, ColorReset)
+    return nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// jsonObject is a JSON object that remembers its key order
+type jsonObject struct {
+    keys   []string
+    values map[string]any
+}
+
+func newJSONObject() *jsonObject {
+    return &jsonObject{values: map[string]any{}}
+}
+
+func (o *jsonObject) get(key string) (any, bool) {
+    value, ok := o.values[key]
+    return value, ok
+}
+
+// Set a key in place, or append it if it is new. Returns false if nothing changed
+func (o *jsonObject) set(key string, value any) bool {
+    if old, ok := o.values[key]; ok {
+        if jsonEqual(old, value) {
+            return false
+        }
+    } else {
+        o.keys = append(o.keys, key)
+    }
+    o.values[key] = value
+    return true
+}
+
+// Return the object stored under key, creating it if it is missing
+func (o *jsonObject) object(key string) *jsonObject {
+    if child, ok := o.values[key].(*jsonObject); ok {
+        return child
+    }
+    child := newJSONObject()
+    o.set(key, child)
+    return child
+}
+
+// packageJSON is a parsed package.json that is written back with the
+// original key order and indentation. Every setter is idempotent
+type packageJSON struct {
+    path    string
+    root    *jsonObject
+    indent  string
+    changes []string
+}
+
+// Helper function to load, edit and save a project-relative package.json
+func editPackageJSON(dir string, edit func(pkg *packageJSON) error) error {
+    path := filepath.Join(dir, "package.json")
+    if !pathExists(path) {
+        return fmt.Errorf("%spackage.json does not exist in %s%s", ColorRed, dir, ColorReset)
+    }
+    return editJSONFile(path, dir, edit)
+}
+
+// Load, edit and save any project-relative JSON config the way package.json is.
+// During a dry run the edit is applied to the file if it is already on disk, or
+// to an empty document if a command would create it, and only the changes are planned
+func editJSONFile(path string, dir string, edit func(pkg *packageJSON) error) error {
+    if opts.dryRun {
+        pkg := &packageJSON{path: path, root: newJSONObject(), indent: "  "}
+        if _, err := os.Stat(projectPath(path)); err == nil {
+            loaded, err := loadPackageJSON(path)
+            if err != nil {
+                return err
+            }
+            pkg = loaded
+        }
+        if err := edit(pkg); err != nil {
+            return err
+        }
+        if len(pkg.changes) > 0 {
+            recordPlan("edit", fmt.Sprintf("%s: %s", path, strings.Join(pkg.changes, ", ")), dir)
+        }
+        return nil
+    }
+
+    pkg, err := loadPackageJSON(path)
+    if err != nil {
+        return err
+    }
+    if err := edit(pkg); err != nil {
+        return err
+    }
+    return pkg.save()
+}
+
+// Parse a JSON file, which may have comments and trailing commas like
+// tsconfig.json. Neither survives save
+func loadPackageJSON(path string) (*packageJSON, error) {
+    data, err := os.ReadFile(projectPath(path))
+    if err != nil {
+        return nil, fmt.Errorf("%sfailed to read %s: %w%s", ColorRed, path, err, ColorReset)
+    }
+    data = stripJSONComments(data)
+    root, err := parseOrderedJSON(data)
+    if err != nil {
+        return nil, fmt.Errorf("%sfailed to parse %s: %w%s", ColorRed, path, err, ColorReset)
+    }
+    obj, ok := root.(*jsonObject)
+    if !ok {
+        return nil, fmt.Errorf("%s%s is not a JSON object%s", ColorRed, path, ColorReset)
+    }
+    return &packageJSON{path: path, root: obj, indent: detectIndent(data)}, nil
+}
+
+// Write the file back if any setter changed it
+func (p *packageJSON) save() error {
+    if len(p.changes) == 0 {
+        return nil
+    }
+    var buf bytes.Buffer
+    writeOrderedJSON(&buf, p.root, p.indent, "")
+    buf.WriteByte('\n')
+    if err := writeFile(p.path, buf.Bytes(), 0644); err != nil {
+        return fmt.Errorf("%sfailed to update %s: %w%s", ColorRed, p.path, err, ColorReset)
+    }
+    fmt.Printf("%sUpdated %s: %s.%s\n", ColorGreen, p.path, strings.Join(p.changes, ", "), ColorReset)
+    return nil
+}
+
+func (p *packageJSON) setIn(section string, key string, value any) {
+    if p.root.object(section).set(key, value) {
+        p.changes = append(p.changes, fmt.Sprintf("%s.%s", section, key))
+    }
+}
+
+func (p *packageJSON) setScript(name string, command string) {
+    p.setIn("scripts", name, command)
+}
+
+func (p *packageJSON) setDependency(name string, version string) {
+    p.setIn("dependencies", name, version)
+}
+
+func (p *packageJSON) setDevDependency(name string, version string) {
+    p.setIn("devDependencies", name, version)
+}
+
+func (p *packageJSON) setEngine(name string, versionRange string) {
+    p.setIn("engines", name, versionRange)
+}
+
+// Set a top-level field such as "type", "private" or "packageManager"
+func (p *packageJSON) setField(key string, value any) {
+    if p.root.set(key, value) {
+        p.changes = append(p.changes, key)
+    }
+}
+
+func (p *packageJSON) setWorkspaces(globs []string) {
+    values := make([]any, len(globs))
+    for i, glob := range globs {
+        values[i] = glob
+    }
+    p.setField("workspaces", values)
+}
+
+// Drop // and /* */ comments, and commas before a closing } or ],
+// leaving strings alone
+func stripJSONComments(data []byte) []byte {
+    out := make([]byte, 0, len(data))
+    comma := -1 // index in out of a comma that may turn out to be trailing
+    for i := 0; i < len(data); i++ {
+        c := data[i]
+        switch {
+        case c == '"':
+            end := i + 1
+            for end < len(data) && data[end] != '"' {
+                if data[end] == '\\' {
+                    end++
+                }
+                end++
+            }
+            end = min(end, len(data)-1)
+            out = append(out, data[i:end+1]...)
+            i = end
+            comma = -1
+            continue
+        case c == '/' && i+1 < len(data) && data[i+1] == '/':
+            for i < len(data) && data[i] != '\n' {
+                i++
+            }
+            i--
+            continue
+        case c == '/' && i+1 < len(data) && data[i+1] == '*':
+            end := bytes.Index(data[i+2:], []byte("*/"))
+            if end < 0 {
+                i = len(data)
+            } else {
+                i += end + 3
+            }
+            continue
+        case c == '}' || c == ']':
+            if comma >= 0 {
+                out[comma] = ' '
+            }
+        case c == ',':
+            out = append(out, c)
+            comma = len(out) - 1
+            continue
+        }
+        if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+            comma = -1
+        }
+        out = append(out, c)
+    }
+    return out
+}
+
+// Decode JSON keeping object key order. Objects become *jsonObject,
+// numbers json.Number, everything else the usual encoding/json types
+func parseOrderedJSON(data []byte) (any, error) {
+    dec := json.NewDecoder(bytes.NewReader(data))
+    dec.UseNumber()
+    value, err := decodeOrderedJSON(dec)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := dec.Token(); err != io.EOF {
+        return nil, fmt.Errorf("unexpected data after the top-level value")
+    }
+    return value, nil
+}
+
+func decodeOrderedJSON(dec *json.Decoder) (any, error) {
+    tok, err := dec.Token()
+    if err != nil {
+        return nil, err
+    }
+    switch t := tok.(type) {
+    case json.Delim:
+        switch t {
+        case '{':
+            obj := newJSONObject()
+            for dec.More() {
+                keyTok, err := dec.Token()
+                if err != nil {
+                    return nil, err
+                }
+                key := keyTok.(string)
+                value, err := decodeOrderedJSON(dec)
+                if err != nil {
+                    return nil, err
+                }
+                if _, dup := obj.values[key]; !dup {
+                    obj.keys = append(obj.keys, key)
+                }
+                obj.values[key] = value
+            }
+            _, err := dec.Token()
+            return obj, err
+        case '[':
+            items := []any{}
+            for dec.More() {
+                value, err := decodeOrderedJSON(dec)
+                if err != nil {
+                    return nil, err
+                }
+                items = append(items, value)
+            }
+            _, err := dec.Token()
+            return items, err
+        }
+        return nil, fmt.Errorf("unexpected %v", t)
+    default:
+        return tok, nil
+    }
+}
+
+func writeOrderedJSON(buf *bytes.Buffer, value any, indent string, prefix string) {
+    switch v := value.(type) {
+    case *jsonObject:
+        if len(v.keys) == 0 {
+            buf.WriteString("{}")
+            return
+        }
+        buf.WriteString("{\n")
+        for i, key := range v.keys {
+            buf.WriteString(prefix + indent)
+            writeJSONScalar(buf, key)
+            buf.WriteString(": ")
+            writeOrderedJSON(buf, v.values[key], indent, prefix+indent)
+            if i < len(v.keys)-1 {
+                buf.WriteByte(',')
+            }
+            buf.WriteByte('\n')
+        }
+        buf.WriteString(prefix + "}")
+    case []any:
+        if len(v) == 0 {
+            buf.WriteString("[]")
+            return
+        }
+        buf.WriteString("[\n")
+        for i, item := range v {
+            buf.WriteString(prefix + indent)
+            writeOrderedJSON(buf, item, indent, prefix+indent)
+            if i < len(v)-1 {
+                buf.WriteByte(',')
+            }
+            buf.WriteByte('\n')
+        }
+        buf.WriteString(prefix + "]")
+    default:
+        writeJSONScalar(buf, v)
+    }
+}
+
+func writeJSONScalar(buf *bytes.Buffer, value any) {
+    var out bytes.Buffer
+    enc := json.NewEncoder(&out)
+    enc.SetEscapeHTML(false)
+    enc.Encode(value)
+    buf.Write(bytes.TrimRight(out.Bytes(), "\n"))
+}
+
+// Compare two decoded JSON values by their encoding
+func jsonEqual(a, b any) bool {
+    var bufA, bufB bytes.Buffer
+    writeOrderedJSON(&bufA, a, "", "")
+    writeOrderedJSON(&bufB, b, "", "")
+    return bufA.String() == bufB.String()
+}
+
+// Use the indentation of the first indented line, or two spaces
+func detectIndent(data []byte) string {
+    for _, line := range strings.Split(string(data), "\n") {
+        trimmed := strings.TrimLeft(line, " \t")
+        if trimmed != "" && len(trimmed) < len(line) {
+            return line[:len(line)-len(trimmed)]
+        }
+    }
+    return "  "
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+    "bytes"
+    "reflect"
+    "testing"
+)
+
+// Parse a package.json the way loadPackageJSON does, without touching disk
+func parseTestPackageJSON(t *testing.T, input string) *packageJSON {
+    t.Helper()
+    data := stripJSONComments([]byte(input))
+    root, err := parseOrderedJSON(data)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    return &packageJSON{path: "package.json", root: root.(*jsonObject), indent: detectIndent(data)}
+}
+
+func (p *packageJSON) encode() string {
+    var buf bytes.Buffer
+    writeOrderedJSON(&buf, p.root, p.indent, "")
+    buf.WriteByte('\n')
+    return buf.String()
+}
+
+func TestPackageJSONRoundTrip(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+    }{
+        {
+            name:  "two spaces, keys out of alphabetical order",
+            input: "{\n  \"name\": \"app\",\n  \"version\": \"1.0.0\",\n  \"scripts\": {\n    \"dev\": \"vite\",\n    \"build\": \"tsc && vite build\"\n  },\n  \"private\": true\n}\n",
+        },
+        {
+            name:  "four spaces with nested lists",
+            input: "{\n    \"workspaces\": [\n        \"frontend\",\n        \"backend\"\n    ],\n    \"devDependencies\": {}\n}\n",
+        },
+        {
+            name:  "tabs, numbers and escapes",
+            input: "{\n\t\"port\": 3000,\n\t\"ratio\": 1.50,\n\t\"description\": \"<a & b>\\n\"\n}\n",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := parseTestPackageJSON(t, tt.input).encode(); got != tt.input {
+                t.Errorf("got\n%s\nwant\n%s", got, tt.input)
+            }
+        })
+    }
+}
+
+func TestPackageJSONSetters(t *testing.T) {
+    input := "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"dev\": \"vite\"\n  }\n}\n"
+    tests := []struct {
+        name    string
+        edit    func(pkg *packageJSON)
+        want    string
+        changes []string
+    }{
+        {
+            name:    "same value changes nothing",
+            edit:    func(pkg *packageJSON) { pkg.setScript("dev", "vite") },
+            want:    input,
+            changes: nil,
+        },
+        {
+            name: "setting a value twice changes it once",
+            edit: func(pkg *packageJSON) {
+                for i := 0; i < 2; i++ {
+                    pkg.setDevDependency("vite", "^5.4.0")
+                    pkg.setEngine("node", ">=20")
+                }
+            },
+            want:    "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"dev\": \"vite\"\n  },\n  \"devDependencies\": {\n    \"vite\": \"^5.4.0\"\n  },\n  \"engines\": {\n    \"node\": \">=20\"\n  }\n}\n",
+            changes: []string{"devDependencies.vite", "engines.node"},
+        },
+        {
+            name:    "existing key keeps its place",
+            edit:    func(pkg *packageJSON) { pkg.setField("name", "web"); pkg.setScript("dev", "vite --host") },
+            want:    "{\n  \"name\": \"web\",\n  \"scripts\": {\n    \"dev\": \"vite --host\"\n  }\n}\n",
+            changes: []string{"name", "scripts.dev"},
+        },
+        {
+            name: "new keys are appended",
+            edit: func(pkg *packageJSON) {
+                pkg.setScript("build", "vite build")
+                pkg.setDependency("zod", "^3.23.8")
+                pkg.setDevDependency("vite", "^5.4.0")
+                pkg.setEngine("node", ">=20")
+                pkg.setWorkspaces([]string{"frontend"})
+            },
+            want:    "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"dev\": \"vite\",\n    \"build\": \"vite build\"\n  },\n  \"dependencies\": {\n    \"zod\": \"^3.23.8\"\n  },\n  \"devDependencies\": {\n    \"vite\": \"^5.4.0\"\n  },\n  \"engines\": {\n    \"node\": \">=20\"\n  },\n  \"workspaces\": [\n    \"frontend\"\n  ]\n}\n",
+            changes: []string{"scripts.build", "dependencies.zod", "devDependencies.vite", "engines.node", "workspaces"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            pkg := parseTestPackageJSON(t, input)
+            tt.edit(pkg)
+            if got := pkg.encode(); got != tt.want {
+                t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+            }
+            if !reflect.DeepEqual(pkg.changes, tt.changes) {
+                t.Errorf("got changes %v, want %v", pkg.changes, tt.changes)
+            }
+        })
+    }
+}
+
+func TestStripJSONComments(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        want  string
+    }{
+        {
+            name:  "line and block comments",
+            input: "{\n  /* Bundler mode */\n  \"a\": 1, // one\n  \"b\": 2\n}",
+            want:  "{\n  \n  \"a\": 1, \n  \"b\": 2\n}",
+        },
+        {
+            name:  "comment markers inside strings",
+            input: `{"include": ["src/**/*.ts"], "url": "http://x"}`,
+            want:  `{"include": ["src/**/*.ts"], "url": "http://x"}`,
+        },
+        {
+            name:  "trailing commas",
+            input: "{\"a\": [1, 2,],\n}",
+            want:  "{\"a\": [1, 2 ] \n}",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := string(stripJSONComments([]byte(tt.input))); got != tt.want {
+                t.Errorf("got %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
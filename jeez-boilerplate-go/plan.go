@@ -0,0 +1,84 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// planEntry is one side effect jeez would have performed during a dry run
+type planEntry struct {
+    action string
+    target string
+    dir    string
+}
+
+// Dry-run state: the ordered plan and the paths earlier planned steps would have created
+var (
+    plan        []planEntry
+    plannedPath = map[string]bool{}
+)
+
+// Helper function to add an entry to the dry-run plan; dir is project-relative
+func recordPlan(action string, target string, dir string) {
+    plan = append(plan, planEntry{action: action, target: target, dir: filepath.Clean(dir)})
+}
+
+// Helper function to write a project-relative file, or plan the write during a dry run
+func writeFile(path string, data []byte, perm os.FileMode) error {
+    if opts.dryRun {
+        recordPlan("write", fmt.Sprintf("%s (%d bytes)", path, len(data)), "")
+        plannedPath[projectPath(path)] = true
+        return nil
+    }
+    return os.WriteFile(projectPath(path), data, perm)
+}
+
+// Helper function to check for a project-relative file or directory. During a dry run
+// a path that only an unexecuted command would create is assumed to exist, and the plan says so
+func pathExists(path string) bool {
+    if _, err := os.Stat(projectPath(path)); err == nil {
+        return true
+    }
+    if !opts.dryRun {
+        return false
+    }
+    if !plannedPath[projectPath(path)] {
+        recordPlan("assume", path+" exists (created by an earlier command)", "")
+    }
+    return true
+}
+
+// Like pathExists, but never adds an assumption to the plan
+func pathExistsQuiet(path string) bool {
+    if _, err := os.Stat(projectPath(path)); err == nil {
+        return true
+    }
+    return opts.dryRun && plannedPath[projectPath(path)]
+}
+
+// Print the recorded plan
+func printPlan() {
+    fmt.Printf("%s%sDry run: jeez would perform %d actions.%s\n", ColorBold, ColorCyan, len(plan), ColorReset)
+    for i, entry := range plan {
+        where := ""
+        if entry.dir != "." && entry.dir != "" {
+            where = fmt.Sprintf("  %s(in %s)%s", ColorBlue, entry.dir, ColorReset)
+        }
+        fmt.Printf("%3d. %s%-7s%s %s%s\n", i+1, ColorYellow, entry.action, ColorReset, entry.target, where)
+    }
+    fmt.Printf("%sNothing was written and no commands were run.%s\n", ColorGreen, ColorReset)
+}
+
+func quoteArgs(args []string) string {
+    quoted := make([]string, len(args))
+    for i, arg := range args {
+        if arg == "" || strings.ContainsAny(arg, " \t\"'$") {
+            quoted[i] = fmt.Sprintf("%q", arg)
+        } else {
+            quoted[i] = arg
+        }
+    }
+    return strings.Join(quoted, " ")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "fmt"
+    "net"
+)
+
+// Find free host ports for the database, backend and frontend dev server so
+// several projects can run side by side. Each starts from its usual default
+// and moves up past ports that are in use
+func (p *project) allocatePorts() {
+    taken := map[int]bool{}
+    for _, entry := range []struct {
+        label string
+        port  *int
+    }{
+        {"database", &p.dbPort},
+        {"backend", &p.backendPort},
+        {"frontend", &p.frontendPort},
+    } {
+        wanted := *entry.port
+        *entry.port = freePort(wanted, taken)
+        taken[*entry.port] = true
+        if *entry.port != wanted {
+            fmt.Printf("%sPort %d is in use; the %s will use port %d.%s\n", ColorYellow, wanted, entry.label, *entry.port, ColorReset)
+        }
+    }
+}
+
+// The first port from start on that nothing listens on and jeez has not handed
+// out, or one the OS picks when the next hundred are all busy
+func freePort(start int, taken map[int]bool) int {
+    for port := start; port < start+100 && port <= 65535; port++ {
+        if !taken[port] && portIsFree(port) {
+            return port
+        }
+    }
+    for {
+        l, err := net.Listen("tcp", ":0")
+        if err != nil {
+            return start
+        }
+        port := l.Addr().(*net.TCPAddr).Port
+        l.Close()
+        if !taken[port] {
+            return port
+        }
+    }
+}
+
+func portIsFree(port int) bool {
+    l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+    if err != nil {
+        return false
+    }
+    l.Close()
+    return true
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "os/exec"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ToolRecipe is a recipe that runs external programs. Preflight checks
+// each of them before anything is created
+type ToolRecipe interface {
+    Recipe
+    // RequiredTools is empty when the flags already rule the recipe out
+    RequiredTools() []string
+}
+
+// What the flags rule out before any prompt, so preflight checks only the
+// tools of recipes that can still run. An unset flag rules nothing out
+func flagsAllowFrontend() bool { return opts.layout != "backend" && opts.frontend != "skip" }
+func flagsAllowBackend() bool  { return opts.layout != "frontend" && opts.backend != "skip" }
+
+// A TypeScript backend, the only kind that needs the package manager
+func flagsAllowNodeBackend() bool {
+    return flagsAllowBackend() && opts.backend != "go" && opts.backend != "fastapi"
+}
+
+func flagDeclined(answer optionalBool) bool { return answer.set && !answer.value }
+
+// toolRequirement says how to read a tool's version and the oldest version jeez supports
+type toolRequirement struct {
+    versionArgs []string
+    minVersion  string
+}
+
+var toolRequirements = map[string]toolRequirement{
+    "git":     {[]string{"--version"}, "2.28.0"},
+    "node":    {[]string{"--version"}, "18.0.0"},
+    "npm":     {[]string{"--version"}, "8.0.0"},
+    "npx":     {[]string{"--version"}, "8.0.0"},
+    "pnpm":    {[]string{"--version"}, "8.0.0"},
+    "yarn":    {[]string{"--version"}, "1.22.0"},
+    "bun":     {[]string{"--version"}, "1.2.0"}, // the first to write the text bun.lock
+    "docker":  {[]string{"--version"}, "20.10.0"},
+    "go":      {[]string{"version"}, "1.22.0"},
+    "python3": {[]string{"--version"}, "3.9.0"},
+}
+
+// toolStatus is what preflight found for one tool
+type toolStatus struct {
+    name    string
+    path    string
+    version string
+    problem string
+    users   []string
+}
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Check every tool the recipes need, print what was found, and offer to drop
+// the recipes whose tools are missing or too old
+func preflight(ordered []Recipe) map[string]string {
+    var statuses []*toolStatus
+    byTool := map[string]*toolStatus{}
+    for _, r := range ordered {
+        tr, ok := r.(ToolRecipe)
+        if !ok {
+            continue
+        }
+        for _, tool := range tr.RequiredTools() {
+            status, seen := byTool[tool]
+            if !seen {
+                status = checkTool(tool)
+                byTool[tool] = status
+                statuses = append(statuses, status)
+            }
+            status.users = append(status.users, r.Name())
+        }
+    }
+
+    fmt.Printf("%sChecking your toolchain...%s\n", ColorBold, ColorReset)
+    broken := map[string]string{}
+    for _, status := range statuses {
+        if status.problem == "" {
+            fmt.Printf("  %s✔ %s %s%s\n", ColorGreen, status.name, status.version, ColorReset)
+            continue
+        }
+        fmt.Printf("  %s✘ %s: %s (needed by %s)%s\n", ColorRed, status.name, status.problem, strings.Join(status.users, ", "), ColorReset)
+        for _, user := range status.users {
+            if _, seen := broken[user]; !seen {
+                broken[user] = status.name + " " + status.problem
+            }
+        }
+    }
+    if len(broken) == 0 {
+        return nil
+    }
+
+    names := make([]string, 0, len(broken))
+    for _, r := range ordered {
+        if _, ok := broken[r.Name()]; ok {
+            names = append(names, r.Name())
+        }
+    }
+    if getYesNoResponse(fmt.Sprintf("Skip the recipes that need them (%s)", strings.Join(names, ", ")), optionalBool{}) {
+        return broken
+    }
+    fmt.Printf("%sContinuing with every recipe; the ones above may fail.%s\n", ColorYellow, ColorReset)
+    return nil
+}
+
+// Find a tool on PATH and compare its version to the minimum
+func checkTool(name string) *toolStatus {
+    status := &toolStatus{name: name}
+    path, err := exec.LookPath(name)
+    if err != nil {
+        status.problem = "not found on PATH"
+        return status
+    }
+    status.path = path
+
+    req, known := toolRequirements[name]
+    if !known {
+        status.version = "(version not checked)"
+        return status
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    out, err := exec.CommandContext(ctx, path, req.versionArgs...).Output()
+    if err != nil {
+        status.problem = fmt.Sprintf("could not read version: %v", err)
+        return status
+    }
+    status.version = versionPattern.FindString(string(out))
+    if status.version == "" {
+        status.problem = fmt.Sprintf("could not read version from %q", strings.TrimSpace(string(out)))
+        return status
+    }
+    if compareVersions(status.version, req.minVersion) < 0 {
+        status.problem = fmt.Sprintf("version %s is older than %s", status.version, req.minVersion)
+    }
+    return status
+}
+
+// Compare dotted versions numerically; missing parts count as 0
+func compareVersions(a, b string) int {
+    pa := versionPattern.FindStringSubmatch(a)
+    pb := versionPattern.FindStringSubmatch(b)
+    for i := 1; i <= 3; i++ {
+        var na, nb int
+        if pa != nil {
+            na, _ = strconv.Atoi(pa[i])
+        }
+        if pb != nil {
+            nb, _ = strconv.Atoi(pb[i])
+        }
+        if na != nb {
+            if na < nb {
+                return -1
+            }
+            return 1
+        }
+    }
+    return 0
+}
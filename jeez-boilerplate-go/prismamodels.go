@@ -0,0 +1,632 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// prismaModel is one model of the generated schema.prisma
+type prismaModel struct {
+    name   string
+    fields []prismaField
+}
+
+// prismaField is one line of a model. typ is a Prisma scalar type or the name
+// of another model, which makes the field a relation
+type prismaField struct {
+    name     string
+    typ      string
+    list     bool
+    optional bool
+    unique   bool
+    id       bool
+    // def is the @default argument, already quoted for strings
+    def string
+    // fk and references are set on the side of a relation that holds the key
+    fk         string
+    references string
+    // relationName is set when two models are linked more than once, or a model to itself
+    relationName string
+    // manyToMany marks both lists of an implicit many-to-many relation
+    manyToMany bool
+    // objectID marks the generated id and the keys pointing at it, stored as ObjectIds on MongoDB
+    objectID bool
+}
+
+// fieldRef points at models[model].fields[field] while relations are resolved
+type fieldRef struct {
+    model int
+    field int
+}
+
+var prismaScalarTypes = []string{"String", "Boolean", "Int", "BigInt", "Float", "Decimal", "DateTime", "Json", "Bytes"}
+
+// The functions @default accepts, by field type
+var prismaDefaultFunctions = map[string][]string{
+    "String":   {"uuid()", "cuid()"},
+    "Int":      {"autoincrement()"},
+    "BigInt":   {"autoincrement()"},
+    "DateTime": {"now()"},
+}
+
+var (
+    prismaModelName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+    prismaFieldName = regexp.MustCompile(`^[a-z][A-Za-z0-9_]*$`)
+)
+
+// The model every project gets unless it defines its own
+func defaultPrismaModels() []prismaModel {
+    models, _ := resolvePrismaModels([]prismaModel{{
+        name: "User",
+        fields: []prismaField{
+            {name: "email", typ: "String", unique: true},
+            {name: "firstName", typ: "String"},
+            {name: "lastName", typ: "String"},
+        },
+    }})
+    return models
+}
+
+func hasPrismaModel(models []prismaModel, name string) bool {
+    for _, m := range models {
+        if m.name == name {
+            return true
+        }
+    }
+    return false
+}
+
+func checkPrismaModelName(name string, models []prismaModel) error {
+    if !prismaModelName.MatchString(name) {
+        return fmt.Errorf("model name %q must start with a capital letter and use only letters, digits and _", name)
+    }
+    if contains(prismaScalarTypes, name) || name == "Prisma" || name == "PrismaClient" {
+        return fmt.Errorf("model name %q is reserved by Prisma", name)
+    }
+    for _, m := range models {
+        // The client names both User and user as prisma.user
+        if strings.EqualFold(m.name, name) {
+            return fmt.Errorf("model %s is already defined", m.name)
+        }
+    }
+    return nil
+}
+
+// Parse one field written the way schema.prisma spells it, like
+// "title String @unique", "age Int? @default(18)", "author User" or "posts Post[]"
+func parsePrismaField(line string) (prismaField, error) {
+    parts := strings.Fields(line)
+    if len(parts) < 2 {
+        return prismaField{}, fmt.Errorf("%q needs a name and a type, like \"title String\"", strings.TrimSpace(line))
+    }
+    f := prismaField{name: parts[0], typ: parts[1]}
+    if !prismaFieldName.MatchString(f.name) {
+        return f, fmt.Errorf("field name %q must start with a lowercase letter and use only letters, digits and _", f.name)
+    }
+    switch {
+    case strings.HasSuffix(f.typ, "[]"):
+        f.list = true
+        f.typ = strings.TrimSuffix(f.typ, "[]")
+    case strings.HasSuffix(f.typ, "?"):
+        f.optional = true
+        f.typ = strings.TrimSuffix(f.typ, "?")
+    }
+    if !prismaModelName.MatchString(f.typ) {
+        return f, fmt.Errorf("field %s has an invalid type %q", f.name, parts[1])
+    }
+
+    rest := strings.TrimSpace(line)
+    rest = strings.TrimSpace(rest[len(parts[0]):])
+    rest = strings.TrimSpace(rest[len(parts[1]):])
+    for rest != "" {
+        word := strings.Fields(rest)[0]
+        switch {
+        case word == "@id":
+            f.id = true
+            rest = rest[len(word):]
+        case word == "@unique":
+            f.unique = true
+            rest = rest[len(word):]
+        case strings.HasPrefix(rest, "@default("):
+            value, n, err := cutDefaultArgument(rest[len("@default("):])
+            if err != nil {
+                return f, fmt.Errorf("field %s: %w", f.name, err)
+            }
+            f.def = strings.TrimSpace(value)
+            if f.def == "" {
+                return f, fmt.Errorf("field %s: @default needs a value", f.name)
+            }
+            rest = rest[len("@default(")+n:]
+        default:
+            return f, fmt.Errorf("field %s: unknown attribute %q (use @id, @unique or @default(...))", f.name, word)
+        }
+        rest = strings.TrimSpace(rest)
+    }
+
+    if contains(prismaScalarTypes, f.typ) {
+        return f, normalizePrismaDefault(&f)
+    }
+    // Any other type names a model; resolvePrismaModels checks that it exists
+    if f.id || f.def != "" {
+        return f, fmt.Errorf("field %s: a relation to %s cannot have @id or @default", f.name, f.typ)
+    }
+    if f.list && f.unique {
+        return f, fmt.Errorf("field %s: a list of %s cannot be @unique", f.name, f.typ)
+    }
+    return f, nil
+}
+
+// Find the parenthesis that closes @default(, skipping quoted strings and
+// calls like uuid(). Returns the argument and how much of s it used
+func cutDefaultArgument(s string) (string, int, error) {
+    depth, quoted := 0, false
+    for i := 0; i < len(s); i++ {
+        switch c := s[i]; {
+        case quoted && c == '\\':
+            i++
+        case c == '"':
+            quoted = !quoted
+        case quoted:
+        case c == '(':
+            depth++
+        case c == ')':
+            if depth == 0 {
+                return s[:i], i + 1, nil
+            }
+            depth--
+        }
+    }
+    return "", 0, errors.New("@default is missing its closing parenthesis")
+}
+
+// Check a scalar field's @default against its type, quoting bare strings
+func normalizePrismaDefault(f *prismaField) error {
+    if f.def == "" {
+        return nil
+    }
+    if f.list {
+        return fmt.Errorf("field %s: list fields cannot have a default", f.name)
+    }
+    if strings.HasSuffix(f.def, "()") {
+        if !contains(prismaDefaultFunctions[f.typ], f.def) {
+            return fmt.Errorf("field %s: %s is not a default for %s", f.name, f.def, f.typ)
+        }
+        return nil
+    }
+
+    valid := true
+    switch f.typ {
+    case "String":
+        if s, err := strconv.Unquote(f.def); err == nil {
+            f.def = strconv.Quote(s)
+        } else {
+            f.def = strconv.Quote(f.def)
+        }
+    case "Int", "BigInt":
+        _, err := strconv.ParseInt(f.def, 10, 64)
+        valid = err == nil
+    case "Float", "Decimal":
+        _, err := strconv.ParseFloat(f.def, 64)
+        valid = err == nil
+    case "Boolean":
+        valid = f.def == "true" || f.def == "false"
+    default:
+        return fmt.Errorf("field %s: %s fields only take %s as a default", f.name, f.typ, strings.Join(append(prismaDefaultFunctions[f.typ], "no value"), " or "))
+    }
+    if !valid {
+        return fmt.Errorf("field %s: %s is not a valid %s", f.name, f.def, f.typ)
+    }
+    return nil
+}
+
+// Check one model's fields and give it a uuid id unless a field is marked @id
+func checkPrismaModel(m prismaModel) (prismaModel, error) {
+    fields := make([]prismaField, 0, len(m.fields)+1)
+    seen := map[string]bool{}
+    ids := 0
+    for _, f := range m.fields {
+        if seen[f.name] {
+            return m, fmt.Errorf("%s.%s is defined twice", m.name, f.name)
+        }
+        seen[f.name] = true
+        if f.id {
+            ids++
+            if f.list || f.optional || !contains([]string{"String", "Int", "BigInt"}, f.typ) {
+                return m, fmt.Errorf("%s.%s: @id needs a required String, Int or BigInt field", m.name, f.name)
+            }
+        }
+        fields = append(fields, f)
+    }
+    switch {
+    case ids > 1:
+        return m, fmt.Errorf("%s has more than one @id field", m.name)
+    case ids == 0 && seen["id"]:
+        return m, fmt.Errorf("%s.id must be marked @id, or renamed", m.name)
+    case ids == 0:
+        id := prismaField{name: "id", typ: "String", id: true, def: "uuid()", objectID: true}
+        fields = append([]prismaField{id}, fields...)
+    }
+    return prismaModel{name: m.name, fields: fields}, nil
+}
+
+func prismaIDField(m prismaModel) prismaField {
+    for _, f := range m.fields {
+        if f.id {
+            return f
+        }
+    }
+    return prismaField{}
+}
+
+// Check every model, make sure relations point at models that exist, and add
+// what Prisma needs for each relation: the field on the other side, the
+// foreign key and, where it would be ambiguous, a relation name
+func resolvePrismaModels(input []prismaModel) ([]prismaModel, error) {
+    if len(input) == 0 {
+        return nil, errors.New("define at least one model")
+    }
+    models := make([]prismaModel, 0, len(input))
+    index := map[string]int{}
+    for _, m := range input {
+        if err := checkPrismaModelName(m.name, models); err != nil {
+            return nil, err
+        }
+        checked, err := checkPrismaModel(m)
+        if err != nil {
+            return nil, err
+        }
+        index[m.name] = len(models)
+        models = append(models, checked)
+    }
+
+    isRelation := func(f prismaField) bool { return !contains(prismaScalarTypes, f.typ) }
+    for _, m := range models {
+        for _, f := range m.fields {
+            if _, ok := index[f.typ]; isRelation(f) && !ok {
+                return nil, fmt.Errorf("%s.%s points to %s, which is not a model or a Prisma type", m.name, f.name, f.typ)
+            }
+        }
+    }
+
+    paired := map[fieldRef]bool{}
+    var pairs [][2]fieldRef
+    generatedKeys := map[string]bool{}
+    field := func(ref fieldRef) *prismaField { return &models[ref.model].fields[ref.field] }
+
+    // Look in the related model for a relation back that is not paired yet
+    findPartner := func(from fieldRef, list bool) (fieldRef, bool) {
+        other := index[field(from).typ]
+        for j, g := range models[other].fields {
+            ref := fieldRef{other, j}
+            if ref != from && !paired[ref] && g.typ == models[from.model].name && g.list == list {
+                return ref, true
+            }
+        }
+        return fieldRef{}, false
+    }
+    // The field added on the other side of from: base, or base prefixed with
+    // from's name when a second relation between the same models took base
+    backName := func(from fieldRef, base string) string {
+        for _, g := range models[index[field(from).typ]].fields {
+            if g.name == base {
+                return field(from).name + upperFirst(base)
+            }
+        }
+        return base
+    }
+    addField := func(model int, f prismaField) (fieldRef, error) {
+        for _, g := range models[model].fields {
+            if g.name == f.name {
+                return fieldRef{}, fmt.Errorf("%s needs a field %s for its relation to %s, but the name is taken; add the relation field yourself", models[model].name, f.name, f.typ)
+            }
+        }
+        models[model].fields = append(models[model].fields, f)
+        return fieldRef{model, len(models[model].fields) - 1}, nil
+    }
+    // Point the owning side at the other model's id through <field>Id,
+    // reusing a scalar field of that name when the model already has one
+    addForeignKey := func(owner fieldRef, oneToOne bool) error {
+        o := field(owner)
+        id := prismaIDField(models[index[o.typ]])
+        o.fk, o.references, o.unique = o.name+"Id", id.name, false
+        key := prismaField{name: o.fk, typ: id.typ, optional: o.optional, unique: oneToOne, objectID: id.objectID}
+        for j, g := range models[owner.model].fields {
+            if g.name != key.name {
+                continue
+            }
+            if g.typ != id.typ || g.list || g.id {
+                return fmt.Errorf("%s.%s holds the key for %s, so it must be a %s", models[owner.model].name, g.name, o.name, id.typ)
+            }
+            models[owner.model].fields[j] = key
+            return nil
+        }
+        generatedKeys[models[owner.model].name+"."+key.name] = true
+        _, err := addField(owner.model, key)
+        return err
+    }
+    pair := func(a, b fieldRef) {
+        paired[a], paired[b] = true, true
+        pairs = append(pairs, [2]fieldRef{a, b})
+    }
+
+    // Single relations own the key. They pair with a list on the other side
+    // (one-to-many) or another single relation (one-to-one)
+    for i := range models {
+        for j := 0; j < len(models[i].fields); j++ {
+            ref := fieldRef{i, j}
+            f := *field(ref)
+            if !isRelation(f) || f.list || paired[ref] {
+                continue
+            }
+            var partner fieldRef
+            found := false
+            if !f.unique {
+                partner, found = findPartner(ref, true)
+            }
+            oneToOne := f.unique
+            if !found {
+                partner, found = findPartner(ref, false)
+                oneToOne = oneToOne || found
+            }
+
+            owner := ref
+            if !found {
+                back := prismaField{name: backName(ref, pluralize(lowerFirst(models[i].name))), typ: models[i].name, list: true}
+                if oneToOne {
+                    back = prismaField{name: backName(ref, lowerFirst(models[i].name)), typ: models[i].name, optional: true}
+                }
+                var err error
+                if partner, err = addField(index[f.typ], back); err != nil {
+                    return nil, err
+                }
+            } else if oneToOne {
+                g := field(partner)
+                if (g.unique && !f.unique) || (!g.optional && f.optional) {
+                    owner, partner = partner, ref
+                }
+                // The side without the key has to be optional
+                if !field(partner).optional {
+                    return nil, fmt.Errorf("one side of the one-to-one relation between %s and %s must be optional", models[ref.model].name, models[partner.model].name)
+                }
+                field(partner).unique = false
+            }
+            if err := addForeignKey(owner, oneToOne); err != nil {
+                return nil, err
+            }
+            pair(owner, partner)
+        }
+    }
+
+    // Lists left over pair with a list on the other side (many-to-many), or
+    // get a single relation there that owns the key
+    for i := range models {
+        for j := 0; j < len(models[i].fields); j++ {
+            ref := fieldRef{i, j}
+            f := *field(ref)
+            if !isRelation(f) || !f.list || paired[ref] {
+                continue
+            }
+            partner, found := findPartner(ref, true)
+            if !found {
+                var err error
+                // A required link to the same model could never be filled for the first row
+                back := prismaField{name: backName(ref, lowerFirst(models[i].name)), typ: models[i].name, optional: f.typ == models[i].name}
+                if partner, err = addField(index[f.typ], back); err != nil {
+                    return nil, err
+                }
+                if err := addForeignKey(partner, false); err != nil {
+                    return nil, err
+                }
+            } else {
+                field(ref).manyToMany, field(partner).manyToMany = true, true
+            }
+            pair(ref, partner)
+        }
+    }
+
+    // Prisma needs a name to tell relations apart when two models share more than one
+    modelPair := func(p [2]fieldRef) [2]string {
+        a, b := models[p[0].model].name, models[p[1].model].name
+        if a > b {
+            a, b = b, a
+        }
+        return [2]string{a, b}
+    }
+    counts := map[[2]string]int{}
+    for _, p := range pairs {
+        counts[modelPair(p)]++
+    }
+    for _, p := range pairs {
+        if names := modelPair(p); counts[names] > 1 || names[0] == names[1] {
+            name := models[p[0].model].name + upperFirst(field(p[0]).name)
+            field(p[0]).relationName, field(p[1]).relationName = name, name
+        }
+    }
+
+    // Keep each generated key next to its relation field
+    for i, m := range models {
+        fields := make([]prismaField, 0, len(m.fields))
+        for _, f := range m.fields {
+            if generatedKeys[m.name+"."+f.name] {
+                continue
+            }
+            fields = append(fields, f)
+            if generatedKeys[m.name+"."+f.fk] {
+                for _, g := range m.fields {
+                    if g.name == f.fk {
+                        fields = append(fields, g)
+                    }
+                }
+            }
+        }
+        models[i].fields = fields
+    }
+    return models, nil
+}
+
+// Reject fields the chosen database cannot store
+func checkPrismaProvider(models []prismaModel, provider string) error {
+    for _, m := range models {
+        for _, f := range m.fields {
+            if f.list && contains(prismaScalarTypes, f.typ) && provider != "postgresql" && provider != "mongodb" {
+                return fmt.Errorf("%s.%s: lists of %s need PostgreSQL or MongoDB", m.name, f.name, f.typ)
+            }
+            if f.def == "autoincrement()" && provider == "mongodb" {
+                return fmt.Errorf("%s.%s: MongoDB does not support autoincrement()", m.name, f.name)
+            }
+            if f.manyToMany && provider == "mongodb" {
+                return fmt.Errorf("%s.%s: MongoDB does not support implicit many-to-many relations; link both models to a model of their own", m.name, f.name)
+            }
+        }
+    }
+    return nil
+}
+
+// Write the model blocks of schema.prisma, with columns aligned like prisma format
+func renderPrismaModels(models []prismaModel, provider string) string {
+    var b strings.Builder
+    for i, m := range models {
+        if i > 0 {
+            b.WriteString("\n")
+        }
+        rows := make([][3]string, len(m.fields))
+        nameWidth, typeWidth := 0, 0
+        for j, f := range m.fields {
+            typ := f.typ
+            if f.list {
+                typ += "[]"
+            } else if f.optional {
+                typ += "?"
+            }
+            rows[j] = [3]string{f.name, typ, strings.Join(f.attributes(provider), " ")}
+            nameWidth = max(nameWidth, len(f.name))
+            typeWidth = max(typeWidth, len(typ))
+        }
+        fmt.Fprintf(&b, "model %s {\n", m.name)
+        for _, row := range rows {
+            line := fmt.Sprintf("    %-*s %-*s %s", nameWidth, row[0], typeWidth, row[1], row[2])
+            b.WriteString(strings.TrimRight(line, " ") + "\n")
+        }
+        b.WriteString("}\n")
+    }
+    return b.String()
+}
+
+func (f prismaField) attributes(provider string) []string {
+    mongo := provider == "mongodb"
+    var attrs []string
+    if f.id {
+        attrs = append(attrs, "@id")
+    }
+    if f.id && f.objectID && mongo {
+        attrs = append(attrs, "@default(auto())")
+    } else if f.def != "" {
+        attrs = append(attrs, "@default("+f.def+")")
+    }
+    if f.unique {
+        attrs = append(attrs, "@unique")
+    }
+    if f.relationName != "" || f.fk != "" {
+        var args []string
+        if f.relationName != "" {
+            args = append(args, strconv.Quote(f.relationName))
+        }
+        if f.fk != "" {
+            args = append(args, "fields: ["+f.fk+"]", "references: ["+f.references+"]")
+        }
+        attrs = append(attrs, "@relation("+strings.Join(args, ", ")+")")
+    }
+    if f.id && mongo {
+        attrs = append(attrs, `@map("_id")`)
+    }
+    if f.objectID && mongo {
+        attrs = append(attrs, "@db.ObjectId")
+    }
+    return attrs
+}
+
+// Ask for models until the name is left empty, then check the relations
+// between them. With no models the project gets the default User model
+func promptPrismaModels() []prismaModel {
+    reader := bufio.NewReader(os.Stdin)
+    readLine := func(prompt string) string {
+        fmt.Printf("%s%s%s", ColorYellow, prompt, ColorReset)
+        line, _ := reader.ReadString('\n')
+        return strings.TrimSpace(line)
+    }
+
+    fmt.Println("Enter one field per line as schema.prisma writes it: `title String @unique`,")
+    fmt.Println("`age Int? @default(18)`, `author User` or `posts Post[]`. Each model gets an id unless a field is @id.")
+    var models []prismaModel
+    for {
+        name := readLine("Model name (leave empty to finish): ")
+        if name == "reset" {
+            models = nil
+            continue
+        }
+        if name == "" {
+            if len(models) == 0 {
+                fmt.Printf("%sNo models defined; using the User model.%s\n", ColorYellow, ColorReset)
+                return defaultPrismaModels()
+            }
+            resolved, err := resolvePrismaModels(models)
+            if err == nil {
+                return resolved
+            }
+            fmt.Printf("%s%v. Add the missing model, or enter reset to start over.%s\n", ColorRed, err, ColorReset)
+            continue
+        }
+        if err := checkPrismaModelName(name, models); err != nil {
+            fmt.Printf("%s%v.%s\n", ColorRed, err, ColorReset)
+            continue
+        }
+
+        model := prismaModel{name: name}
+        for {
+            line := readLine(fmt.Sprintf("  %s field (leave empty to finish): ", name))
+            if line == "" {
+                break
+            }
+            f, err := parsePrismaField(line)
+            if err == nil {
+                _, err = checkPrismaModel(prismaModel{name: name, fields: append(model.fields[:len(model.fields):len(model.fields)], f)})
+            }
+            if err != nil {
+                fmt.Printf("%s%v.%s\n", ColorRed, err, ColorReset)
+                continue
+            }
+            model.fields = append(model.fields, f)
+        }
+        models = append(models, model)
+    }
+}
+
+func lowerFirst(s string) string {
+    if s == "" {
+        return s
+    }
+    return strings.ToLower(s[:1]) + s[1:]
+}
+
+func upperFirst(s string) string {
+    if s == "" {
+        return s
+    }
+    return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Naive English plural for generated list fields: post -> posts, category -> categories
+func pluralize(s string) string {
+    switch {
+    case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+        return s[:len(s)-1] + "ies"
+    case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+        return s + "es"
+    }
+    return s + "s"
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestResolvePrismaModels(t *testing.T) {
+    tests := []struct {
+        name   string
+        models []modelSpec
+        want   string
+    }{
+        {
+            name: "one-to-many gets the list and the key",
+            models: []modelSpec{
+                {Name: "User", Fields: []string{"email String @unique"}},
+                {Name: "Post", Fields: []string{"title String", "author User"}},
+            },
+            want: `model User {
+    id    String @id @default(uuid())
+    email String @unique
+    posts Post[]
+}
+
+model Post {
+    id       String @id @default(uuid())
+    title    String
+    author   User   @relation(fields: [authorId], references: [id])
+    authorId String
+}
+`,
+        },
+        {
+            name: "many-to-many keeps both lists",
+            models: []modelSpec{
+                {Name: "Post", Fields: []string{"tags Tag[]"}},
+                {Name: "Tag", Fields: []string{"posts Post[]"}},
+            },
+            want: `model Post {
+    id   String @id @default(uuid())
+    tags Tag[]
+}
+
+model Tag {
+    id    String @id @default(uuid())
+    posts Post[]
+}
+`,
+        },
+        {
+            name: "one-to-one puts the key on the required side",
+            models: []modelSpec{
+                {Name: "User", Fields: []string{"profile Profile?"}},
+                {Name: "Profile", Fields: []string{"user User"}},
+            },
+            want: `model User {
+    id      String   @id @default(uuid())
+    profile Profile?
+}
+
+model Profile {
+    id     String @id @default(uuid())
+    user   User   @relation(fields: [userId], references: [id])
+    userId String @unique
+}
+`,
+        },
+        {
+            name: "self-relation is named and optional",
+            models: []modelSpec{
+                {Name: "Employee", Fields: []string{"reports Employee[]"}},
+            },
+            want: `model Employee {
+    id         String     @id @default(uuid())
+    reports    Employee[] @relation("EmployeeReports")
+    employee   Employee?  @relation("EmployeeReports", fields: [employeeId], references: [id])
+    employeeId String?
+}
+`,
+        },
+        {
+            name: "two relations to the same model get their own names",
+            models: []modelSpec{
+                {Name: "User", Fields: []string{"name String"}},
+                {Name: "Post", Fields: []string{"author User", "editor User"}},
+            },
+            want: `model User {
+    id          String @id @default(uuid())
+    name        String
+    posts       Post[] @relation("PostAuthor")
+    editorPosts Post[] @relation("PostEditor")
+}
+
+model Post {
+    id       String @id @default(uuid())
+    author   User   @relation("PostAuthor", fields: [authorId], references: [id])
+    authorId String
+    editor   User   @relation("PostEditor", fields: [editorId], references: [id])
+    editorId String
+}
+`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            models, err := prismaModelsFromSpec(tt.models)
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if got := renderPrismaModels(models, "postgresql"); got != tt.want {
+                t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestPrismaModelErrors(t *testing.T) {
+    tests := []struct {
+        name     string
+        models   []modelSpec
+        provider string
+        want     string
+    }{
+        {
+            name:   "unknown type",
+            models: []modelSpec{{Name: "Post", Fields: []string{"author Person"}}},
+            want:   "models: Post.author points to Person, which is not a model or a Prisma type",
+        },
+        {
+            name:   "bad field line",
+            models: []modelSpec{{Name: "Post", Fields: []string{"title String", "Title String"}}},
+            want:   "models[0].fields[1]",
+        },
+        {
+            name:     "scalar list on SQLite",
+            models:   []modelSpec{{Name: "Post", Fields: []string{"tags String[]"}}},
+            provider: "sqlite",
+            want:     "Post.tags: lists of String need PostgreSQL or MongoDB",
+        },
+        {
+            name: "many-to-many on MongoDB",
+            models: []modelSpec{
+                {Name: "Post", Fields: []string{"tags Tag[]"}},
+                {Name: "Tag", Fields: []string{"posts Post[]"}},
+            },
+            provider: "mongodb",
+            want:     "Post.tags: MongoDB does not support implicit many-to-many relations",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            models, err := prismaModelsFromSpec(tt.models)
+            if err == nil {
+                err = checkPrismaProvider(models, tt.provider)
+            }
+            if err == nil {
+                t.Fatalf("expected an error containing %q", tt.want)
+            }
+            if !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("got error %q, want it to contain %q", err, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Recipe is one optional piece of project setup. Recipes run in registration
+// order, except that a recipe always runs after the recipes it depends on, and
+// is skipped when any of them did not run
+type Recipe interface {
+    // Name is a short unique id, used in Dependencies and the summary
+    Name() string
+    Description() string
+    Dependencies() []string
+    // Prompt asks the recipe's questions (or reads their flags) and
+    // reports whether Apply should run
+    Prompt(p *project) (bool, error)
+    Apply(p *project) error
+}
+
+// RollbackRecipe is a recipe that can undo changes a snapshot cannot see,
+// like Git config. Rollback runs before the project tree is restored
+type RollbackRecipe interface {
+    Recipe
+    Rollback(p *project) error
+}
+
+// project is the state recipes share during one run
+type project struct {
+    name      string
+    frontend  bool
+    backend   bool
+    framework frontendFramework
+    server    backendFramework
+    database  databaseEngine
+    orm       ormTool
+    models    []prismaModel
+    monorepo  monorepoTool
+    ci        ciProvider
+    compose   composeFile
+    pm        packageManager
+    pmVersion string
+    // composeApps adds the api and web services to compose; see docker.go
+    composeApps bool
+    // Host ports, moved off the defaults by allocatePorts when they are in use
+    dbPort       int
+    backendPort  int
+    frontendPort int
+    // Generated by generateSecrets; see secrets.go
+    dbPassword    string
+    sessionSecret string
+    disabled      map[string]string
+    selected      map[string]bool
+    applied       map[string]bool
+}
+
+func newProject(name string) *project {
+    return &project{
+        name:         name,
+        dbPort:       10001,
+        backendPort:  3000,
+        frontendPort: 5173,
+        pm:           packageManagerFor("npm"),
+        database:     databaseEngines[0],
+        selected:     map[string]bool{},
+        applied:      map[string]bool{},
+    }
+}
+
+// The built-in recipes, in the order the wizard asks about them
+var recipes = []Recipe{
+    &packageManagerRecipe{},
+    &gitRecipe{},
+    &directoriesRecipe{},
+    &viteRecipe{},
+    &tailwindRecipe{},
+    &storybookRecipe{},
+    &backendRecipe{},
+    &databaseRecipe{},
+    &ormRecipe{},
+    &envRecipe{},
+    &workspaceRecipe{},
+    &sharedRecipe{},
+    &dockerRecipe{},
+    &ciRecipe{},
+    &remoteRecipe{},
+}
+
+// Add a recipe to the run; call it from an init function
+func registerRecipe(r Recipe) {
+    recipes = append(recipes, r)
+}
+
+// Sort recipes so each comes after its dependencies, keeping registration
+// order otherwise. Unknown dependencies and cycles are errors
+func orderedRecipes() ([]Recipe, error) {
+    byName := map[string]Recipe{}
+    for _, r := range recipes {
+        if _, dup := byName[r.Name()]; dup {
+            return nil, fmt.Errorf("recipe %q is registered twice", r.Name())
+        }
+        byName[r.Name()] = r
+    }
+
+    const (
+        unvisited = iota
+        visiting
+        done
+    )
+    state := map[string]int{}
+    var ordered []Recipe
+    var visit func(r Recipe, path []string) error
+    visit = func(r Recipe, path []string) error {
+        switch state[r.Name()] {
+        case done:
+            return nil
+        case visiting:
+            return fmt.Errorf("recipe dependency cycle: %s", strings.Join(append(path, r.Name()), " -> "))
+        }
+        state[r.Name()] = visiting
+        for _, dep := range r.Dependencies() {
+            depRecipe, ok := byName[dep]
+            if !ok {
+                return fmt.Errorf("recipe %q depends on unknown recipe %q", r.Name(), dep)
+            }
+            if err := visit(depRecipe, append(path, r.Name())); err != nil {
+                return err
+            }
+        }
+        state[r.Name()] = done
+        ordered = append(ordered, r)
+        return nil
+    }
+    for _, r := range recipes {
+        if err := visit(r, nil); err != nil {
+            return nil, err
+        }
+    }
+    return ordered, nil
+}
+
+// Run every recipe in two passes. First each recipe whose dependencies were
+// selected asks its questions, so templates can see every selected feature.
+// Then the selected recipes are applied, skipping any whose dependency failed.
+// On failure the user can roll a recipe back, keep its files, or delete the project
+func runRecipes(p *project, ordered []Recipe) {
+    records := make([]stepRecord, len(ordered))
+    byName := map[string]*stepRecord{}
+
+    for i, r := range ordered {
+        records[i].name = r.Name()
+        byName[r.Name()] = &records[i]
+        if reason, ok := p.disabled[r.Name()]; ok {
+            records[i].status = stepSkipped
+            records[i].reason = reason
+            records[i].blocked = true
+            continue
+        }
+        if missing := missingDependency(r, p.selected); missing != "" {
+            // Skipping because the user declined the dependency is not a problem
+            dep := byName[missing]
+            records[i].status = stepSkipped
+            records[i].reason = "needs " + missing
+            records[i].blocked = dep != nil && (dep.blocked || dep.status == stepFailed)
+            continue
+        }
+        run, err := r.Prompt(p)
+        switch {
+        case err != nil && !errors.Is(err, errSkipped):
+            records[i].status = stepFailed
+            records[i].err = err
+            handleError("preparing "+r.Name(), err)
+        case !run || err != nil:
+            records[i].status = stepSkipped
+        default:
+            p.selected[r.Name()] = true
+        }
+    }
+
+    for i, r := range ordered {
+        if !p.selected[r.Name()] {
+            continue
+        }
+        if missing := missingDependency(r, p.applied); missing != "" {
+            records[i].status = stepSkipped
+            records[i].reason = missing + " did not complete"
+            records[i].blocked = true
+            continue
+        }
+        applyRecipe(p, r, &records[i])
+    }
+
+    stepRecords = append(stepRecords, records...)
+}
+
+func missingDependency(r Recipe, done map[string]bool) string {
+    for _, dep := range r.Dependencies() {
+        if !done[dep] {
+            return dep
+        }
+    }
+    return ""
+}
+
+func applyRecipe(p *project, r Recipe, record *stepRecord) {
+    var snap *snapshot
+    if !opts.dryRun {
+        var err error
+        if snap, err = takeSnapshot(projectRoot); err != nil {
+            // snap is nil, so the step still runs but cannot be rolled back
+            handleError("recording project state before "+r.Name(), err)
+        }
+    }
+
+    // Services are kept in memory, so a rolled-back recipe's are dropped here
+    compose := p.compose.clone()
+    err := r.Apply(p)
+    if snap != nil {
+        record.created = snap.created(projectRoot)
+    }
+    switch {
+    case err == nil:
+        record.status = stepSucceeded
+        p.applied[r.Name()] = true
+    case errors.Is(err, errSkipped):
+        record.status = stepSkipped
+    default:
+        record.status = stepFailed
+        record.err = err
+        handleError("running the "+r.Name()+" recipe", err)
+        if snap != nil {
+            record.status = handleStepFailure(p, r, snap, record.status)
+            if record.status == stepRolledBack {
+                p.compose = compose
+            }
+        }
+    }
+}
+
+// Print every recipe with its dependencies and description
+func listRecipes() error {
+    ordered, err := orderedRecipes()
+    if err != nil {
+        return err
+    }
+    for _, r := range ordered {
+        deps := ""
+        if len(r.Dependencies()) > 0 {
+            deps = fmt.Sprintf(" %s(needs %s)%s", ColorBlue, strings.Join(r.Dependencies(), ", "), ColorReset)
+        }
+        fmt.Printf("%s%-12s%s %s%s\n", ColorBold, r.Name(), ColorReset, r.Description(), deps)
+    }
+    return nil
+}
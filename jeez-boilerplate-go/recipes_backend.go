@@ -0,0 +1,566 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// backendFramework is one server the backend recipe can scaffold. Its template
+// set holds the entry file and any framework config
+type backendFramework struct {
+    name  string
+    label string
+    // language is "typescript" (installed with the package manager), "go" or "python"
+    language        string
+    dependencies    []string
+    devDependencies []string
+    // scripts are added to package.json in this order
+    scripts [][2]string
+    // esm marks frameworks that run as ES modules ("type": "module")
+    esm bool
+    // entry is the compiled file node runs in production
+    entry string
+}
+
+// In the same order as backendChoices
+var backendFrameworks = []backendFramework{
+    {
+        name:            "express",
+        label:           "Express (with TypeScript)",
+        language:        "typescript",
+        dependencies:    []string{"express", "cors", "dotenv"},
+        devDependencies: []string{"typescript", "ts-node", "nodemon", "@types/express", "@types/cors"},
+        scripts:         [][2]string{{"start", "nodemon src/server.ts"}, {"build", "tsc"}},
+        entry:           "dist/server.js",
+    },
+    {
+        name:            "fastify",
+        label:           "Fastify",
+        language:        "typescript",
+        dependencies:    []string{"fastify", "@fastify/cors", "dotenv"},
+        devDependencies: []string{"typescript", "tsx", "@types/node"},
+        scripts:         [][2]string{{"dev", "tsx watch src/server.ts"}, {"build", "tsc"}, {"start", "node dist/server.js"}},
+        entry:           "dist/server.js",
+    },
+    {
+        name:            "hono",
+        label:           "Hono (on Node)",
+        language:        "typescript",
+        dependencies:    []string{"hono", "@hono/node-server", "dotenv"},
+        devDependencies: []string{"typescript", "tsx", "@types/node"},
+        scripts:         [][2]string{{"dev", "tsx watch src/index.ts"}, {"build", "tsc"}, {"start", "node dist/index.js"}},
+        esm:             true,
+        entry:           "dist/index.js",
+    },
+    {
+        name:            "nestjs",
+        label:           "NestJS",
+        language:        "typescript",
+        dependencies:    []string{"@nestjs/common", "@nestjs/core", "@nestjs/platform-express", "@nestjs/config", "reflect-metadata", "rxjs"},
+        devDependencies: []string{"typescript", "@nestjs/cli", "@nestjs/schematics", "@types/node", "@types/express"},
+        scripts:         [][2]string{{"dev", "nest start --watch"}, {"build", "nest build"}, {"start", "node dist/main.js"}},
+        entry:           "dist/main.js",
+    },
+    {
+        name:     "go",
+        label:    "Go (net/http)",
+        language: "go",
+    },
+    {
+        name:     "fastapi",
+        label:    "FastAPI (Python)",
+        language: "python",
+    },
+}
+
+// backendRecipe scaffolds a TypeScript or Go server with CORS and env loading
+type backendRecipe struct {
+    // framework is the name chosen in Prompt
+    framework string
+}
+
+func (r *backendRecipe) Name() string        { return "backend" }
+func (r *backendRecipe) Description() string { return "Scaffold a Node, Go or Python backend" }
+
+// Go and FastAPI need no Node package manager. Until Prompt has run the
+// framework is whatever --backend says, and an unknown one may be TypeScript
+func (r *backendRecipe) Dependencies() []string {
+    framework := r.framework
+    if framework == "" {
+        framework = opts.backend
+    }
+    if framework == "go" || framework == "fastapi" {
+        return []string{"directories"}
+    }
+    return []string{"directories", "packagemanager"}
+}
+
+func (r *backendRecipe) RequiredTools() []string {
+    if !flagsAllowBackend() {
+        return nil
+    }
+    switch opts.backend {
+    case "go":
+        return []string{"go"}
+    case "fastapi":
+        return []string{"python3"}
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *backendRecipe) Prompt(p *project) (bool, error) {
+    if !p.backend {
+        return false, nil
+    }
+    items := make([]string, 0, len(backendFrameworks)+1)
+    for _, framework := range backendFrameworks {
+        items = append(items, framework.label)
+    }
+    items = append(items, "Skip")
+
+    choice, answered := presetChoice(opts.backend, "express", backendChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your backend setup:", items, choice, answered))
+    if n > len(backendFrameworks) {
+        fmt.Printf("%sSkipping backend setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.server = backendFrameworks[n-1]
+    r.framework = p.server.name
+    return true, nil
+}
+
+func (r *backendRecipe) Apply(p *project) error {
+    framework := p.server
+    switch framework.language {
+    case "go":
+        return applyGoBackend(p)
+    case "python":
+        return applyFastAPIBackend(p)
+    }
+
+    // Create package.json if it doesn't exist
+    if err := runPackageManager("backend", p.pm.initCommand()); err != nil {
+        return fmt.Errorf("%sfailed to initialize %s: %w%s", ColorRed, p.pm.name, err, ColorReset)
+    }
+
+    // Install the framework with its CORS and dotenv support
+    if err := runPackageManager("backend", p.pm.addCommand(framework.dependencies...)); err != nil {
+        return fmt.Errorf("%sfailed to install backend dependencies: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runPackageManager("backend", p.pm.addDevCommand(framework.devDependencies...)); err != nil {
+        return fmt.Errorf("%sfailed to install backend dev dependencies: %w%s", ColorRed, err, ColorReset)
+    }
+
+    // Write tsconfig.json and a simple server with CORS and dotenv support
+    if err := renderTemplates(framework.name, p.templateData()); err != nil {
+        return err
+    }
+
+    // Add the dev, build and start scripts to package.json
+    if err := editPackageJSON("backend", func(pkg *packageJSON) error {
+        for _, script := range framework.scripts {
+            pkg.setScript(script[0], script[1])
+        }
+        if framework.esm {
+            pkg.setField("type", "module")
+        }
+        p.setPackageManagerField(pkg)
+        return nil
+    }); err != nil {
+        fmt.Printf("%sWarning: Failed to add scripts to package.json:%s %v\n", ColorYellow, ColorReset, err)
+    }
+
+    fmt.Printf("%sJeez! %s backend setup with TypeScript, CORS, and dotenv complete.%s\n", ColorGreen, framework.label, ColorReset)
+    return nil
+}
+
+// Set up backend/ as a Go module with a net/http server in cmd/server and a Makefile
+func applyGoBackend(p *project) error {
+    module := projectSlug(p.name) + "/backend"
+    if err := runCommand("backend", "go", "mod", "init", module); err != nil {
+        return fmt.Errorf("%sfailed to initialize Go module %s: %w%s", ColorRed, module, err, ColorReset)
+    }
+
+    // Write cmd/server/main.go with CORS and .env.local loading, and the Makefile
+    if err := renderTemplates("go", p.templateData()); err != nil {
+        return err
+    }
+
+    // Record the database driver main.go imports, if any
+    if err := runCommand("backend", "go", "mod", "tidy"); err != nil {
+        return fmt.Errorf("%sfailed to tidy Go module: %w%s", ColorRed, err, ColorReset)
+    }
+
+    fmt.Printf("%sJeez! Go backend setup with CORS and env loading complete.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Set up backend/ as a Python package with a FastAPI app in app/main.py, installed
+// into backend/.venv
+func applyFastAPIBackend(p *project) error {
+    // Write pyproject.toml, app/main.py and, with a database, the SQLModel config
+    if err := renderTemplates("fastapi", p.templateData()); err != nil {
+        return err
+    }
+
+    if err := runCommand("backend", "python3", "-m", "venv", ".venv"); err != nil {
+        return fmt.Errorf("%sfailed to create virtual environment: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runCommand("backend", ".venv/bin/python", "-m", "pip", "install", "-e", "."); err != nil {
+        return fmt.Errorf("%sfailed to install backend dependencies: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := addToGitignore("Python virtualenv and build caches", []string{".venv", "__pycache__", "*.egg-info"}); err != nil {
+        return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+    }
+
+    fmt.Printf("%sJeez! FastAPI backend setup with CORS and dotenv complete.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Turn a project name into a package or module name: lower case, with anything
+// outside letters, digits, dots, dashes and underscores replaced by dashes
+func projectSlug(name string) string {
+    var b strings.Builder
+    for _, c := range strings.ToLower(name) {
+        switch {
+        case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+            b.WriteRune(c)
+        default:
+            b.WriteRune('-')
+        }
+    }
+    slug := strings.Trim(b.String(), "-.")
+    if slug == "" {
+        slug = "app"
+    }
+    return slug
+}
+
+// The database the project's database service creates: the project name in
+// lower case with anything outside letters, digits and underscores replaced
+// by underscores, so it needs no quoting in a URL, a shell or SQL
+func databaseName(name string) string {
+    var b strings.Builder
+    for _, c := range strings.ToLower(name) {
+        switch {
+        case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_':
+            b.WriteRune(c)
+        default:
+            b.WriteRune('_')
+        }
+    }
+    base := strings.Trim(b.String(), "_")
+    if base == "" {
+        base = "app"
+    }
+    return base + "_db"
+}
+
+// databaseEngine is one database the database recipe can run for the backend
+type databaseEngine struct {
+    name  string
+    label string
+    // prismaProvider is the datasource provider in schema.prisma
+    prismaProvider string
+    // nodeDriver is the package Drizzle, TypeORM and Kysely connect with; empty when they cannot
+    nodeDriver      string
+    nodeDriverTypes string
+    // containerPort is the port the engine listens on inside its container
+    containerPort int
+}
+
+// In the same order as dbChoices
+var databaseEngines = []databaseEngine{
+    {name: "postgres", label: "PostgreSQL", prismaProvider: "postgresql", nodeDriver: "pg", nodeDriverTypes: "@types/pg", containerPort: 5432},
+    {name: "mysql", label: "MySQL", prismaProvider: "mysql", nodeDriver: "mysql2", containerPort: 3306},
+    {name: "sqlite", label: "SQLite (no container)", prismaProvider: "sqlite", nodeDriver: "better-sqlite3", nodeDriverTypes: "@types/better-sqlite3"},
+    {name: "mongodb", label: "MongoDB", prismaProvider: "mongodb", containerPort: 27017},
+}
+
+// DATABASE_URL for this engine, pointing at the compose service on the host port.
+// The SQLite file is relative to the directory that opens it (prisma/ for Prisma)
+func (e databaseEngine) url(port int, dbName string, password string) string {
+    return e.hostURL("localhost", port, dbName, password)
+}
+
+// Like url, for a database reachable under another host name, such as a CI service
+func (e databaseEngine) hostURL(host string, port int, dbName string, password string) string {
+    switch e.name {
+    case "mysql":
+        return fmt.Sprintf("mysql://root:%s@%s:%d/%s", password, host, port, dbName)
+    case "sqlite":
+        return "file:./dev.db"
+    case "mongodb":
+        // Prisma needs a replica set; the compose service runs a single-node one
+        return fmt.Sprintf("mongodb://%s:%d/%s?replicaSet=rs0&directConnection=true", host, port, dbName)
+    }
+    return fmt.Sprintf("postgresql://postgres:%s@%s:%d/%s", password, host, port, dbName)
+}
+
+// The container's settings, which the compose service reads from .env.db so the
+// password stays out of docker-compose.yml. MongoDB runs without auth: a
+// replica set with users would also need a shared key file
+func (e databaseEngine) containerEnv(dbName string, password string) [][2]string {
+    switch e.name {
+    case "mysql":
+        return [][2]string{{"MYSQL_ROOT_PASSWORD", password}, {"MYSQL_DATABASE", dbName}}
+    case "mongodb":
+        return nil
+    }
+    return [][2]string{{"POSTGRES_USER", "postgres"}, {"POSTGRES_PASSWORD", password}, {"POSTGRES_DB", dbName}}
+}
+
+// The compose service that runs this engine, publishing it on the host port.
+// Data lives in a named volume so it survives docker compose down
+func (e databaseEngine) composeService(port int, dbName string) composeService {
+    switch e.name {
+    case "mysql":
+        return composeService{
+            name:    "mysql",
+            image:   "mysql:8",
+            envFile: []string{dbEnvPath},
+            ports:   []string{fmt.Sprintf("%d:%d", port, e.containerPort)},
+            volumes: []string{"mysql-data:/var/lib/mysql"},
+            // $$ keeps the variable for the container's shell instead of compose
+            healthcheck: &composeHealthcheck{test: "mysqladmin ping -h localhost -p$$MYSQL_ROOT_PASSWORD", interval: "5s", timeout: "5s", retries: 10},
+        }
+    case "mongodb":
+        return composeService{
+            name:  "mongodb",
+            image: "mongo:7",
+            // Prisma needs a replica set; the healthcheck initiates a single-node one
+            command: "--replSet rs0 --bind_ip_all",
+            ports:   []string{fmt.Sprintf("%d:%d", port, e.containerPort)},
+            volumes: []string{"mongodb-data:/data/db"},
+            healthcheck: &composeHealthcheck{
+                test:        `echo "try { rs.status() } catch (err) { rs.initiate({_id:'rs0',members:[{_id:0,host:'localhost:27017'}]}) }" | mongosh --port 27017 --quiet`,
+                interval:    "5s",
+                timeout:     "30s",
+                startPeriod: "10s",
+                retries:     30,
+            },
+        }
+    }
+    return composeService{
+        name:        "postgres",
+        image:       "postgres:13",
+        command:     "-c fsync=off -c full_page_writes=off -c synchronous_commit=off -c max_connections=500",
+        envFile:     []string{dbEnvPath},
+        ports:       []string{fmt.Sprintf("%d:%d", port, e.containerPort)},
+        volumes:     []string{"postgres-data:/var/lib/postgresql/data"},
+        healthcheck: &composeHealthcheck{test: "pg_isready -U postgres -d " + dbName, interval: "5s", timeout: "5s", retries: 10},
+    }
+}
+
+// databaseRecipe adds the chosen database to the project's compose file
+type databaseRecipe struct{}
+
+func (r *databaseRecipe) Name() string           { return "database" }
+func (r *databaseRecipe) Description() string    { return "Set up Postgres, MySQL, SQLite or MongoDB" }
+func (r *databaseRecipe) Dependencies() []string { return []string{"directories"} }
+
+func (r *databaseRecipe) RequiredTools() []string {
+    // SQLite is a file and needs no container
+    if !flagsAllowBackend() || opts.db == "none" || opts.db == "sqlite" {
+        return nil
+    }
+    return []string{"docker"}
+}
+
+func (r *databaseRecipe) Prompt(p *project) (bool, error) {
+    if !p.backend {
+        return false, nil
+    }
+    items := make([]string, 0, len(databaseEngines)+1)
+    for _, engine := range databaseEngines {
+        items = append(items, engine.label)
+    }
+    items = append(items, "None")
+
+    choice, answered := presetChoice(opts.db, "postgres", dbChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your database:", items, choice, answered))
+    if n > len(databaseEngines) {
+        fmt.Printf("%sSkipping database setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.database = databaseEngines[n-1]
+    return true, nil
+}
+
+func (r *databaseRecipe) Apply(p *project) error {
+    if p.database.name == "sqlite" {
+        fmt.Printf("%sJeez! SQLite needs no container; the database file is created on first use.%s\n", ColorGreen, ColorReset)
+        return nil
+    }
+    data := p.templateData()
+    if env := p.database.containerEnv(data.DBName, p.dbPassword); env != nil {
+        if err := ignoreEnvFiles(); err != nil {
+            return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+        }
+        var lines []string
+        for _, kv := range env {
+            lines = append(lines, kv[0]+"="+kv[1])
+        }
+        if err := writeFile(dbEnvPath, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+            return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, dbEnvPath, err, ColorReset)
+        }
+    }
+    if err := p.addComposeService(p.database.composeService(data.DBPort, data.DBName)); err != nil {
+        return err
+    }
+    fmt.Printf("%sJeez! %s database setup complete.%s\n", ColorGreen, p.database.label, ColorReset)
+    return nil
+}
+
+// ormTool is one TypeScript ORM the orm recipe can set up. Its template set
+// holds the User model, src/db.ts (the client the servers import) and any
+// migration config
+type ormTool struct {
+    name            string
+    label           string
+    dependencies    []string
+    devDependencies []string
+    // driver says whether the engine's Node driver is installed too; Prisma brings its own
+    driver bool
+    // scripts are added to package.json in this order
+    scripts [][2]string
+}
+
+// In the same order as ormChoices
+var ormTools = []ormTool{
+    {
+        name:            "prisma",
+        label:           "Prisma",
+        dependencies:    []string{"@prisma/client", "dotenv"},
+        devDependencies: []string{"prisma"},
+        scripts:         [][2]string{{"db:generate", "prisma generate"}, {"db:migrate", "prisma migrate dev"}, {"db:studio", "prisma studio"}},
+    },
+    {
+        name:            "drizzle",
+        label:           "Drizzle",
+        dependencies:    []string{"drizzle-orm", "dotenv"},
+        devDependencies: []string{"drizzle-kit"},
+        driver:          true,
+        scripts:         [][2]string{{"db:generate", "drizzle-kit generate"}, {"db:migrate", "drizzle-kit migrate"}, {"db:studio", "drizzle-kit studio"}},
+    },
+    {
+        name:            "typeorm",
+        label:           "TypeORM",
+        dependencies:    []string{"typeorm", "reflect-metadata", "dotenv"},
+        devDependencies: []string{"tsx"},
+        driver:          true,
+        scripts: [][2]string{
+            {"db:generate", "tsx ./node_modules/typeorm/cli.js migration:generate src/migrations/Migration -d src/db.ts"},
+            {"db:migrate", "tsx ./node_modules/typeorm/cli.js migration:run -d src/db.ts"},
+        },
+    },
+    {
+        name:            "kysely",
+        label:           "Kysely",
+        dependencies:    []string{"kysely", "dotenv"},
+        devDependencies: []string{"tsx"},
+        driver:          true,
+        scripts:         [][2]string{{"db:migrate", "tsx src/migrate.ts"}, {"db:rollback", "tsx src/migrate.ts down"}},
+    },
+}
+
+// ormRecipe sets up Prisma, Drizzle, TypeORM or Kysely with a starter User model
+type ormRecipe struct{}
+
+func (r *ormRecipe) Name() string           { return "orm" }
+func (r *ormRecipe) Description() string    { return "Set up an ORM with a User model" }
+func (r *ormRecipe) Dependencies() []string { return []string{"backend"} }
+
+func (r *ormRecipe) RequiredTools() []string {
+    if !flagsAllowNodeBackend() || opts.orm == "none" {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *ormRecipe) Prompt(p *project) (bool, error) {
+    if p.server.language != "typescript" {
+        return false, nil
+    }
+    items := make([]string, 0, len(ormTools)+1)
+    for _, orm := range ormTools {
+        items = append(items, orm.label)
+    }
+    items = append(items, "None")
+
+    choice, answered := presetChoice(opts.orm, "prisma", ormChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your ORM:", items, choice, answered))
+    if n > len(ormTools) {
+        fmt.Printf("%sSkipping ORM setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    orm := ormTools[n-1]
+    if orm.driver && p.selected["database"] && p.database.nodeDriver == "" {
+        return false, fmt.Errorf("%s%s does not support %s%s", ColorRed, orm.label, p.database.label, ColorReset)
+    }
+    p.orm = orm
+
+    if orm.name == "prisma" {
+        p.models = opts.models
+        if p.models == nil {
+            p.models = defaultPrismaModels()
+            // Choosing Prisma by flag means no more questions about it
+            if !opts.yes && opts.orm == "" && getYesNoResponse("Do you want to define your own Prisma models (otherwise a User model)", optionalBool{}) {
+                p.models = promptPrismaModels()
+            }
+        }
+        if err := checkPrismaProvider(p.models, p.database.prismaProvider); err != nil {
+            return false, fmt.Errorf("%s%w%s", ColorRed, err, ColorReset)
+        }
+    }
+    return true, nil
+}
+
+func (r *ormRecipe) Apply(p *project) error {
+    orm := p.orm
+    dependencies := orm.dependencies
+    devDependencies := orm.devDependencies
+    if orm.driver {
+        dependencies = append(dependencies, p.database.nodeDriver)
+        if p.database.nodeDriverTypes != "" {
+            devDependencies = append(devDependencies, p.database.nodeDriverTypes)
+        }
+    }
+
+    if err := runPackageManager("backend", p.pm.addCommand(dependencies...)); err != nil {
+        return fmt.Errorf("%sfailed to install %s: %w%s", ColorRed, orm.label, err, ColorReset)
+    }
+    if err := runPackageManager("backend", p.pm.addDevCommand(devDependencies...)); err != nil {
+        return fmt.Errorf("%sfailed to install %s dev dependencies: %w%s", ColorRed, orm.label, err, ColorReset)
+    }
+    if orm.name == "prisma" {
+        if err := runPackageManager("backend", p.pm.execCommand("prisma", "init")); err != nil {
+            return fmt.Errorf("%sFailed to initialize Prisma: %w%s", ColorRed, err, ColorReset)
+        }
+    }
+
+    // Write the models, src/db.ts and the migration config
+    if err := renderTemplates(orm.name, p.templateData()); err != nil {
+        return err
+    }
+
+    if orm.name == "prisma" {
+        if err := runPackageManager("backend", p.pm.execCommand("prisma", "generate")); err != nil {
+            return fmt.Errorf("%sFailed to generate Prisma client: %w%s", ColorRed, err, ColorReset)
+        }
+    }
+
+    // Add the migration scripts to package.json
+    if err := editPackageJSON("backend", func(pkg *packageJSON) error {
+        for _, script := range orm.scripts {
+            pkg.setScript(script[0], script[1])
+        }
+        return nil
+    }); err != nil {
+        fmt.Printf("%sWarning: Failed to add migration scripts to package.json:%s %v\n", ColorYellow, ColorReset, err)
+    }
+
+    fmt.Printf("%sJeez! %s setup complete.%s\n", ColorGreen, orm.label, ColorReset)
+    return nil
+}
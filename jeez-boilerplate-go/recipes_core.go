@@ -0,0 +1,211 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "strings"
+)
+
+// gitRecipe initializes a repository with a README as the first commit
+type gitRecipe struct{}
+
+func (r *gitRecipe) Name() string           { return "git" }
+func (r *gitRecipe) Description() string    { return "Initialize a Git repository with an initial commit" }
+func (r *gitRecipe) Dependencies() []string { return nil }
+
+func (r *gitRecipe) RequiredTools() []string {
+    if flagDeclined(opts.git) {
+        return nil
+    }
+    return []string{"git"}
+}
+
+func (r *gitRecipe) Prompt(p *project) (bool, error) {
+    if !getYesNoResponse("Do you want to initialize a Git repository", opts.git) {
+        fmt.Printf("%sSkipping Git initialization.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    return true, nil
+}
+
+func (r *gitRecipe) Apply(p *project) error {
+    fmt.Println("Initializing git repo...")
+    if err := runCommand(".", "git", "init"); err != nil {
+        return fmt.Errorf("%sfailed to initialize git repository: %w%s", ColorRed, err, ColorReset)
+    }
+    readmeContent := fmt.Sprintf("# %s", p.name)
+    if err := writeFile("README.md", []byte(readmeContent), 0644); err != nil {
+        return fmt.Errorf("%sfailed to create README.md: %w%s", ColorRed, err, ColorReset)
+    }
+    // Ignore env files before any recipe writes a secret into one
+    if err := ignoreEnvFiles(); err != nil {
+        return fmt.Errorf("%sfailed to create .gitignore: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runCommand(".", "git", "add", "README.md", ".gitignore"); err != nil {
+        return fmt.Errorf("%sfailed to add README.md to git: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runCommand(".", "git", "commit", "-m", "Initial commit for "+p.name); err != nil {
+        return fmt.Errorf("%sfailed to commit README.md: %w%s", ColorRed, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! Git repository initialized successfully.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Add patterns under a comment to the project's .gitignore, skipping those it
+// already has
+func addToGitignore(comment string, patterns []string) error {
+    if opts.dryRun && plannedPath[projectPath(".gitignore")] {
+        recordPlan("edit", ".gitignore: "+strings.Join(patterns, ", "), "")
+        return nil
+    }
+    existing, err := os.ReadFile(projectPath(".gitignore"))
+    if err != nil && !errors.Is(err, fs.ErrNotExist) {
+        return err
+    }
+    lines := strings.Split(string(existing), "\n")
+    var missing []string
+    for _, pattern := range patterns {
+        if !contains(lines, pattern) {
+            missing = append(missing, pattern)
+        }
+    }
+    if len(missing) == 0 {
+        return nil
+    }
+
+    text := strings.TrimRight(string(existing), "\n")
+    if text != "" {
+        text += "\n\n"
+    }
+    text += "# " + comment + "\n" + strings.Join(missing, "\n") + "\n"
+    return writeFile(".gitignore", []byte(text), 0644)
+}
+
+// directoriesRecipe picks the project layout and creates frontend/ and backend/
+type directoriesRecipe struct{}
+
+func (r *directoriesRecipe) Name() string           { return "directories" }
+func (r *directoriesRecipe) Description() string    { return "Create the project directories" }
+func (r *directoriesRecipe) Dependencies() []string { return nil }
+
+func (r *directoriesRecipe) Prompt(p *project) (bool, error) {
+    choice, answered := presetChoice(opts.layout, "fullstack", layoutChoices)
+    switch chooseFromMenu("Select your project setup:", []string{"Frontend", "Backend", "Fullstack"}, choice, answered) {
+    case "1":
+        p.frontend = true
+    case "2":
+        p.backend = true
+    case "3":
+        p.frontend = true
+        p.backend = true
+    }
+    return true, nil
+}
+
+func (r *directoriesRecipe) Apply(p *project) error {
+    switch {
+    case p.frontend && p.backend:
+        fmt.Printf("%sSetting up Frontend and Backend directories...%s\n", ColorBlue, ColorReset)
+    case p.frontend:
+        fmt.Printf("%sSetting up Frontend directory...%s\n", ColorBlue, ColorReset)
+    default:
+        fmt.Printf("%sSetting up Backend directory...%s\n", ColorBlue, ColorReset)
+    }
+    if p.frontend {
+        if err := createDirectory("frontend"); err != nil {
+            return err
+        }
+    }
+    if p.backend {
+        if err := createDirectory("backend"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// remoteRecipe adds an origin remote and pushes main to it
+type remoteRecipe struct {
+    url string
+}
+
+func (r *remoteRecipe) Name() string           { return "remote" }
+func (r *remoteRecipe) Description() string    { return "Add a remote Git repository and push to it" }
+func (r *remoteRecipe) Dependencies() []string { return []string{"git"} }
+
+func (r *remoteRecipe) RequiredTools() []string {
+    if opts.remote == "skip" || flagDeclined(opts.git) {
+        return nil
+    }
+    return []string{"git"}
+}
+
+func (r *remoteRecipe) Prompt(p *project) (bool, error) {
+    // A remote URL cannot be defaulted, so --yes alone skips this recipe
+    remote := flagToggle(opts.remote, "skip")
+    if !remote.set && opts.yes {
+        remote = optionalBool{set: true, value: false}
+    }
+    if !getYesNoResponse("Do you want to set up a remote Git repository", remote) {
+        fmt.Println("Skipping remote Git setup.")
+        return false, nil
+    }
+    r.url = opts.remote
+    if r.url == "" {
+        r.url = promptRemoteURL()
+    }
+    if r.url == "skip" {
+        fmt.Println("Skipping remote Git setup.")
+        return false, nil
+    }
+    return true, nil
+}
+
+func (r *remoteRecipe) Apply(p *project) error {
+    for {
+        err := runCommand(".", "git", "remote", "add", "origin", r.url)
+        if err == nil {
+            break
+        }
+        if opts.remote != "" {
+            return fmt.Errorf("%sfailed to add remote origin: %w%s", ColorRed, err, ColorReset)
+        }
+        fmt.Printf("%sFailed to add remote origin:%s %v\n", ColorRed, ColorReset, err)
+        fmt.Println("Please re-enter the remote repo URL or type 'skip' to skip this step.")
+        if r.url = promptRemoteURL(); r.url == "skip" {
+            fmt.Println("Skipping remote Git setup.")
+            return errSkipped
+        }
+    }
+
+    if err := runCommand(".", "git", "branch", "-M", "main"); err != nil {
+        return fmt.Errorf("%sfailed to rename branch to main: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runCommand(".", "git", "push", "-u", "origin", "main"); err != nil {
+        return fmt.Errorf("%sfailed to push to remote repository: %w%s", ColorRed, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! Git remote repo complete.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// The remote lives in .git, which a snapshot treats as opaque
+func (r *remoteRecipe) Rollback(p *project) error {
+    return runCommand(".", "git", "remote", "remove", "origin")
+}
+
+// Helper function to read a remote URL, or "skip"
+func promptRemoteURL() string {
+    reader := bufio.NewReader(os.Stdin)
+    for {
+        fmt.Printf("%sEnter the remote repo URL: %s", ColorYellow, ColorReset)
+        remoteUrl, _ := reader.ReadString('\n')
+        remoteUrl = strings.TrimSpace(remoteUrl)
+        if remoteUrl == "skip" || isValidURL(remoteUrl) {
+            return remoteUrl
+        }
+        fmt.Printf("%sInvalid URL format. Please enter a valid URL.%s\n", ColorRed, ColorReset)
+    }
+}
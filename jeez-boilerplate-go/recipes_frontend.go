@@ -0,0 +1,211 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+// frontendFramework is one create-vite template and what the follow-up recipes need to know about it
+type frontendFramework struct {
+    name     string
+    label    string
+    template string
+    // stylesheet is the CSS file the template's entry point imports
+    stylesheet string
+    // storybookType is the --type for storybook init; empty when Storybook has no renderer for it
+    storybookType string
+    // typecheck checks the template's TypeScript without emitting anything
+    typecheck string
+}
+
+// In the same order as frontendChoices
+var frontendFrameworks = []frontendFramework{
+    {name: "react", label: "React", template: "react-ts", stylesheet: "src/index.css", storybookType: "react", typecheck: "tsc -b"},
+    {name: "vue", label: "Vue", template: "vue-ts", stylesheet: "src/style.css", storybookType: "vue3", typecheck: "vue-tsc -b"},
+    {name: "svelte", label: "Svelte", template: "svelte-ts", stylesheet: "src/app.css", storybookType: "svelte", typecheck: "svelte-check --tsconfig ./tsconfig.app.json"},
+    {name: "solid", label: "Solid", template: "solid-ts", stylesheet: "src/index.css", typecheck: "tsc -b"},
+    {name: "vanilla", label: "Vanilla TypeScript", template: "vanilla-ts", stylesheet: "src/style.css", storybookType: "html", typecheck: "tsc --noEmit"},
+}
+
+// viteRecipe scaffolds the frontend with create-vite
+type viteRecipe struct{}
+
+func (r *viteRecipe) Name() string           { return "vite" }
+func (r *viteRecipe) Description() string    { return "Scaffold the frontend with Vite" }
+func (r *viteRecipe) Dependencies() []string { return []string{"directories", "packagemanager"} }
+
+func (r *viteRecipe) RequiredTools() []string {
+    if !flagsAllowFrontend() {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *viteRecipe) Prompt(p *project) (bool, error) {
+    if !p.frontend {
+        return false, nil
+    }
+    items := make([]string, 0, len(frontendFrameworks)+1)
+    for _, framework := range frontendFrameworks {
+        items = append(items, framework.label)
+    }
+    items = append(items, "Skip")
+
+    choice, answered := presetChoice(opts.frontend, "react", frontendChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your frontend framework:", items, choice, answered))
+    if n > len(frontendFrameworks) {
+        fmt.Printf("%sSkipping frontend setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.framework = frontendFrameworks[n-1]
+    return true, nil
+}
+
+func (r *viteRecipe) Apply(p *project) error {
+    if err := runPackageManager("frontend", p.pm.createCommand("vite@latest", ".", "--template", p.framework.template)); err != nil {
+        return fmt.Errorf("%sfailed to create Vite project: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := runPackageManager("frontend", p.pm.installCommand()); err != nil {
+        return fmt.Errorf("%sfailed to install dependencies: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := editPackageJSON("frontend", func(pkg *packageJSON) error {
+        pkg.setScript("dev", "vite")
+        p.setPackageManagerField(pkg)
+        return nil
+    }); err != nil {
+        fmt.Printf("%sWarning: Failed to add 'dev' script to package.json:%s %v\n", ColorYellow, ColorReset, err)
+    }
+    if err := p.ensureLockfile("frontend"); err != nil {
+        return err
+    }
+    if err := p.setViteServerPort(); err != nil {
+        return fmt.Errorf("%sfailed to set the dev server port: %w%s", ColorRed, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! Vite setup with %s complete.%s\n", ColorGreen, p.framework.label, ColorReset)
+    return nil
+}
+
+// Pin the dev server to the port allocatePorts chose. strictPort makes Vite stop
+// with an error instead of quietly moving to a port nothing else knows about
+func (p *project) setViteServerPort() error {
+    server := fmt.Sprintf("  server: {\n    port: %d,\n    strictPort: true,\n  },\n", p.frontendPort)
+    return addViteConfig(server, "server:", fmt.Sprintf("server.port %d", p.frontendPort))
+}
+
+// Add an option at the top of the object frontend/vite.config.ts passes to
+// defineConfig, unless the config already has key. what names the option in
+// the dry-run plan
+func addViteConfig(option string, key string, what string) error {
+    const config = "frontend/vite.config.ts"
+    if !opts.dryRun && !pathExistsQuiet(config) {
+        // The vanilla template has no config file
+        return writeFile(config, []byte("import { defineConfig } from 'vite'\n\nexport default defineConfig({\n"+option+"})\n"), 0644)
+    }
+    return insertAfter(config, "defineConfig({", key, option, what)
+}
+
+// tailwindRecipe adds TailwindCSS to the Vite frontend
+type tailwindRecipe struct{}
+
+func (r *tailwindRecipe) Name() string           { return "tailwind" }
+func (r *tailwindRecipe) Description() string    { return "Install TailwindCSS in the frontend" }
+func (r *tailwindRecipe) Dependencies() []string { return []string{"vite"} }
+
+func (r *tailwindRecipe) RequiredTools() []string {
+    if !flagsAllowFrontend() || flagDeclined(opts.tailwind) {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *tailwindRecipe) Prompt(p *project) (bool, error) {
+    return getYesNoResponse("Do you want to install TailwindCSS for "+p.framework.label, opts.tailwind), nil
+}
+
+func (r *tailwindRecipe) Apply(p *project) error {
+    fmt.Printf("%sInstalling TailwindCSS...%s\n", ColorBlue, ColorReset)
+    if err := runPackageManager("frontend", p.pm.addDevCommand("tailwindcss@3", "postcss", "autoprefixer")); err != nil {
+        return fmt.Errorf("%sfailed to install TailwindCSS: %w%s", ColorRed, err, ColorReset)
+    }
+
+    // Write tailwind.config.js scanning this framework's files, and postcss.config.js
+    if err := renderTemplates("tailwind", p.templateData()); err != nil {
+        return err
+    }
+
+    // Put the Tailwind layers at the top of the stylesheet the template already imports
+    stylesheet := "frontend/" + p.framework.stylesheet
+    if err := prependToFile(stylesheet, "@tailwind base;\n@tailwind components;\n@tailwind utilities;\n\n", "@tailwind directives"); err != nil {
+        return fmt.Errorf("%sfailed to add Tailwind directives to %s: %w%s", ColorRed, stylesheet, err, ColorReset)
+    }
+    fmt.Printf("%sTailwindCSS setup complete.%s\n", ColorGreen, ColorReset)
+    return nil
+}
+
+// Helper function to add text at the start of a project-relative file, creating it if needed.
+// what names the change in the dry-run plan
+func prependToFile(path string, text string, what string) error {
+    existing, err := os.ReadFile(projectPath(path))
+    switch {
+    case opts.dryRun && errors.Is(err, fs.ErrNotExist):
+        // An earlier command would write the file
+        recordPlan("edit", fmt.Sprintf("%s: %s", path, what), "")
+        return nil
+    case errors.Is(err, fs.ErrNotExist):
+        if err := ensureDirectory(filepath.Dir(path)); err != nil {
+            return err
+        }
+    case err != nil:
+        return err
+    }
+    if strings.HasPrefix(string(existing), text) {
+        return nil
+    }
+    if opts.dryRun {
+        recordPlan("edit", fmt.Sprintf("%s: %s", path, what), "")
+        return nil
+    }
+    return writeFile(path, append([]byte(text), existing...), 0644)
+}
+
+// storybookRecipe adds Storybook to the Vite frontend
+type storybookRecipe struct{}
+
+func (r *storybookRecipe) Name() string           { return "storybook" }
+func (r *storybookRecipe) Description() string    { return "Install Storybook in the frontend" }
+func (r *storybookRecipe) Dependencies() []string { return []string{"vite"} }
+
+func (r *storybookRecipe) RequiredTools() []string {
+    if !flagsAllowFrontend() || flagDeclined(opts.storybook) {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *storybookRecipe) Prompt(p *project) (bool, error) {
+    if p.framework.storybookType == "" {
+        if opts.storybook.set && opts.storybook.value {
+            return false, fmt.Errorf("%sStorybook does not support %s projects%s", ColorRed, p.framework.label, ColorReset)
+        }
+        return false, nil
+    }
+    return getYesNoResponse("Do you want to install Storybook", opts.storybook), nil
+}
+
+func (r *storybookRecipe) Apply(p *project) error {
+    fmt.Printf("%sInstalling Storybook...%s\n", ColorBlue, ColorReset)
+    if err := runPackageManager("frontend", p.pm.execCommand("storybook@latest", "init", "--type", p.framework.storybookType, "--builder", "vite", "--yes")); err != nil {
+        return fmt.Errorf("%sfailed to install Storybook: %w%s", ColorRed, err, ColorReset)
+    }
+    // storybook init installs with whichever package manager it detects
+    if err := p.ensureLockfile("frontend"); err != nil {
+        return err
+    }
+    fmt.Printf("%sStorybook setup complete.%s\n", ColorGreen, ColorReset)
+    return nil
+}
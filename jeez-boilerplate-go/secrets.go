@@ -0,0 +1,43 @@
+package main
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+)
+
+// The env file the database container reads its password from, next to docker-compose.yml
+const dbEnvPath = ".env.db"
+
+// Generate the database password and the session secret. They are only
+// written to .env.db, .env.api and backend/.env, never printed unless
+// --show-secrets asks for them
+func (p *project) generateSecrets() error {
+    var err error
+    if p.dbPassword, err = randomSecret(24); err != nil {
+        return err
+    }
+    p.sessionSecret, err = randomSecret(32)
+    return err
+}
+
+// n random bytes from crypto/rand, hex-encoded so the secret needs no escaping
+// in a URL or an env file
+func randomSecret(n int) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        return "", fmt.Errorf("failed to read random bytes: %w", err)
+    }
+    return hex.EncodeToString(b), nil
+}
+
+// Print the secrets the project was given, for --show-secrets
+func (p *project) printSecrets() {
+    fmt.Printf("%sSecrets:%s\n", ColorBold, ColorReset)
+    if p.applied["database"] && p.database.containerEnv("", "") != nil {
+        fmt.Printf("  database password: %s\n", p.dbPassword)
+    }
+    if p.applied["env"] {
+        fmt.Printf("  SESSION_SECRET:    %s\n", p.sessionSecret)
+    }
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "strings"
+)
+
+// Where the shared package lives in the workspace
+const sharedDir = "packages/shared"
+
+// The shared package's name, scoped by the project so it cannot clash with a
+// published package
+func (p *project) sharedPackageName() string {
+    return "@" + projectSlug(p.name) + "/shared"
+}
+
+// sharedRecipe adds packages/shared, a workspace package of zod schemas and
+// the types inferred from them, and links both apps to it
+type sharedRecipe struct{}
+
+func (r *sharedRecipe) Name() string           { return "shared" }
+func (r *sharedRecipe) Description() string    { return "Share zod schemas and types between the apps" }
+func (r *sharedRecipe) Dependencies() []string { return []string{"workspace"} }
+
+func (r *sharedRecipe) RequiredTools() []string {
+    if flagDeclined(opts.shared) {
+        return nil
+    }
+    return (&workspaceRecipe{}).RequiredTools()
+}
+
+func (r *sharedRecipe) Prompt(p *project) (bool, error) {
+    if !getYesNoResponse("Do you want a shared package of zod schemas for both apps", opts.shared) {
+        fmt.Printf("%sSkipping the shared package.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    return true, nil
+}
+
+func (r *sharedRecipe) Apply(p *project) error {
+    fmt.Printf("%sCreating %s...%s\n", ColorBlue, p.sharedPackageName(), ColorReset)
+
+    // Write the package with its schemas, and frontend/src/api.ts calling the backend through them
+    if err := renderTemplates("shared", p.templateData()); err != nil {
+        return err
+    }
+    if err := p.addToWorkspace(sharedDir, p.pm.addCommand("zod")); err != nil {
+        return fmt.Errorf("%sfailed to install zod: %w%s", ColorRed, err, ColorReset)
+    }
+    if err := p.addToWorkspace(sharedDir, p.pm.addDevCommand("typescript")); err != nil {
+        return fmt.Errorf("%sfailed to install TypeScript in %s: %w%s", ColorRed, sharedDir, err, ColorReset)
+    }
+
+    // npm links workspace packages by version, the others by the workspace: protocol
+    version := "workspace:*"
+    if p.pm.name == "npm" {
+        version = "*"
+    }
+    for _, app := range workspaceApps {
+        if err := editPackageJSON(app, func(pkg *packageJSON) error {
+            pkg.setDependency(p.sharedPackageName(), version)
+            return nil
+        }); err != nil {
+            return err
+        }
+    }
+    if err := runPackageManager(".", p.pm.installCommand()); err != nil {
+        return fmt.Errorf("%sfailed to link %s: %w%s", ColorRed, p.sharedPackageName(), err, ColorReset)
+    }
+    // The backend loads the compiled package, so build it once now
+    if err := runPackageManager(sharedDir, []string{p.pm.name, "run", "build"}); err != nil {
+        return fmt.Errorf("%sfailed to build %s: %w%s", ColorRed, p.sharedPackageName(), err, ColorReset)
+    }
+
+    // Vite and the frontend's type checks read the TypeScript source directly
+    if err := p.aliasSharedPackage(); err != nil {
+        return fmt.Errorf("%sfailed to add the %s alias to the frontend: %w%s", ColorRed, p.sharedPackageName(), err, ColorReset)
+    }
+    if err := p.addToWorkspace("frontend", p.pm.addDevCommand("@types/node")); err != nil {
+        return fmt.Errorf("%sfailed to install @types/node in the frontend: %w%s", ColorRed, err, ColorReset)
+    }
+
+    if err := addToGitignore("Build output of the shared package", []string{sharedDir + "/dist"}); err != nil {
+        return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! %s is shared by the frontend and backend.%s\n", ColorGreen, p.sharedPackageName(), ColorReset)
+    return nil
+}
+
+// Run a package manager's add command for one workspace package. npm only
+// finds the workspace from its root, so it runs there with -w
+func (p *project) addToWorkspace(dir string, command []string) error {
+    if p.pm.name == "npm" {
+        return runPackageManager(".", append(command, "-w", dir))
+    }
+    return runPackageManager(dir, command)
+}
+
+// Point the frontend at the shared package's source: a path alias in its
+// tsconfig, and the same alias in vite.config.ts. Vite cannot serve the
+// compiled CommonJS to the browser, and the frontend needs no build step this way
+func (p *project) aliasSharedPackage() error {
+    source := "../" + sharedDir + "/src/index.ts"
+    // Every template but vanilla-ts keeps the app's settings in tsconfig.app.json.
+    // During a dry run create-vite has not run yet, so go by the template
+    tsconfig := "frontend/tsconfig.app.json"
+    if !pathExistsQuiet(tsconfig) && (!opts.dryRun || p.framework.name == "vanilla") {
+        tsconfig = "frontend/tsconfig.json"
+    }
+    err := editJSONFile(tsconfig, "", func(cfg *packageJSON) error {
+        if cfg.root.object("compilerOptions").object("paths").set(p.sharedPackageName(), []any{source}) {
+            cfg.changes = append(cfg.changes, "compilerOptions.paths."+p.sharedPackageName())
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+
+    key := fmt.Sprintf("'%s':", p.sharedPackageName())
+    alias := fmt.Sprintf("  resolve: {\n    alias: {\n      %s fileURLToPath(new URL('%s', import.meta.url)),\n    },\n  },\n", key, source)
+    if err := addViteConfig(alias, key, "resolve.alias."+p.sharedPackageName()); err != nil {
+        return err
+    }
+    return prependToFile("frontend/vite.config.ts", "import { fileURLToPath } from 'node:url'\n", "import fileURLToPath")
+}
+
+// Insert text on the line after the first occurrence of marker in a
+// project-relative file, unless the file already contains key, so a rerun
+// adds nothing twice. what names the change in the dry-run plan
+func insertAfter(path string, marker string, key string, text string, what string) error {
+    existing, err := os.ReadFile(projectPath(path))
+    if opts.dryRun && errors.Is(err, fs.ErrNotExist) {
+        // An earlier command would write the file, so its content is unknown
+        recordPlan("assume", fmt.Sprintf("%s has %s", path, marker), "")
+        recordPlan("edit", fmt.Sprintf("%s: %s", path, what), "")
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    content := string(existing)
+    if strings.Contains(content, key) {
+        return nil
+    }
+    start := strings.Index(content, marker)
+    if start < 0 {
+        return fmt.Errorf("%s has no %s", path, marker)
+    }
+    if opts.dryRun {
+        recordPlan("edit", fmt.Sprintf("%s: %s", path, what), "")
+        return nil
+    }
+    start += len(marker)
+    if strings.HasPrefix(content[start:], "\n") {
+        start++
+    }
+    return writeFile(path, []byte(content[:start]+text+content[start:]), 0644)
+}
+
+// zodTypes maps the Prisma scalars to the schema of their JSON form
+var zodTypes = map[string]string{
+    "String":   "z.string()",
+    "Boolean":  "z.boolean()",
+    "Int":      "z.number().int()",
+    "BigInt":   "z.coerce.bigint()",
+    "Float":    "z.number()",
+    "Decimal":  "z.string()",
+    "DateTime": "z.coerce.date()",
+    "Json":     "z.unknown()",
+    "Bytes":    "z.unknown()",
+}
+
+// Write a zod schema and inferred type for each model. Relation fields are
+// left out, since queries return them only when asked to include them
+func renderZodSchemas(models []prismaModel) string {
+    blocks := make([]string, 0, len(models))
+    for _, m := range models {
+        var b strings.Builder
+        schema := lowerFirst(m.name) + "Schema"
+        fmt.Fprintf(&b, "export const %s = z.object({\n", schema)
+        for _, f := range m.fields {
+            typ, ok := zodTypes[f.typ]
+            if !ok {
+                continue
+            }
+            if f.list {
+                typ = "z.array(" + typ + ")"
+            } else if f.optional {
+                typ += ".nullable()"
+            }
+            fmt.Fprintf(&b, "    %s: %s,\n", f.name, typ)
+        }
+        fmt.Fprintf(&b, "});\nexport type %s = z.infer<typeof %s>;", m.name, schema)
+        blocks = append(blocks, b.String())
+    }
+    return strings.Join(blocks, "\n\n")
+}
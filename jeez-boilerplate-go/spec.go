@@ -0,0 +1,518 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// projectSpec describes a whole project in a jeez.yaml or jeez.json file.
+// Every field answers one of the wizard's prompts
+type projectSpec struct {
+    Name           string            `json:"name"`
+    Layout         string            `json:"layout"`
+    PackageManager string            `json:"packageManager"`
+    Bun            *bool             `json:"bun"`
+    Monorepo       string            `json:"monorepo"`
+    Shared         *bool             `json:"shared"`
+    Git            *bool             `json:"git"`
+    Frontend       *frontendSpec     `json:"frontend"`
+    Backend        *backendSpec      `json:"backend"`
+    Database       string            `json:"database"`
+    ORM            string            `json:"orm"`
+    Models         []modelSpec       `json:"models"`
+    Env            map[string]string `json:"env"`
+    Remote         string            `json:"remote"`
+    CI             string            `json:"ci"`
+    Docker         *bool             `json:"docker"`
+    DockerCompose  *bool             `json:"dockerCompose"`
+}
+
+type frontendSpec struct {
+    Framework string `json:"framework"`
+    Tailwind  *bool  `json:"tailwind"`
+    Storybook *bool  `json:"storybook"`
+}
+
+type backendSpec struct {
+    Framework string `json:"framework"`
+}
+
+// modelSpec is one Prisma model; each field is written as in schema.prisma,
+// like "title String @unique" or "author User"
+type modelSpec struct {
+    Name   string   `json:"name"`
+    Fields []string `json:"fields"`
+}
+
+// specError points at the field of the spec file that is wrong
+type specError struct {
+    file  string
+    field string
+    msg   string
+}
+
+func (e *specError) Error() string {
+    if e.field == "" {
+        return fmt.Sprintf("%s: %s", e.file, e.msg)
+    }
+    return fmt.Sprintf("%s: %s: %s", e.file, e.field, e.msg)
+}
+
+// modelsFile is the file --models-file reads: the models section of a spec on its own
+type modelsFile struct {
+    Models []modelSpec `json:"models"`
+}
+
+// Read, decode and validate a spec file
+func loadSpec(path string) (*projectSpec, error) {
+    var spec projectSpec
+    if err := decodeSpecFile(path, &spec); err != nil {
+        return nil, err
+    }
+    if err := spec.validate(); err != nil {
+        err.(*specError).file = path
+        return nil, err
+    }
+    return &spec, nil
+}
+
+// Read and decode a models file, and build the models it lists
+func loadModelsFile(path string) ([]prismaModel, error) {
+    var file modelsFile
+    if err := decodeSpecFile(path, &file); err != nil {
+        return nil, err
+    }
+    if len(file.Models) == 0 {
+        return nil, &specError{file: path, field: "models", msg: "lists no models"}
+    }
+    models, err := prismaModelsFromSpec(file.Models)
+    if err != nil {
+        err.(*specError).file = path
+        return nil, err
+    }
+    return models, nil
+}
+
+// Decode a YAML or JSON file into out, a pointer to a spec struct, rejecting
+// anything that does not match its fields
+func decodeSpecFile(path string, out any) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("failed to read spec file: %w", err)
+    }
+
+    var raw any
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".yaml", ".yml":
+        raw, err = parseYAML(data)
+    case ".json":
+        err = json.Unmarshal(data, &raw)
+    default:
+        return fmt.Errorf("%s: unsupported spec format (want .yaml, .yml or .json)", path)
+    }
+    if err != nil {
+        return &specError{file: path, msg: err.Error()}
+    }
+
+    normalized, err := normalizeSpecValue("", raw, reflect.TypeOf(out).Elem())
+    if err != nil {
+        if se, ok := err.(*specError); ok {
+            se.file = path
+        }
+        return err
+    }
+
+    // The normalized tree matches the struct exactly, so this cannot fail on shape
+    encoded, err := json.Marshal(normalized)
+    if err != nil {
+        return err
+    }
+    if err := json.Unmarshal(encoded, out); err != nil {
+        return &specError{file: path, msg: err.Error()}
+    }
+    return nil
+}
+
+// Walk a decoded YAML/JSON tree against the spec type, rejecting unknown
+// fields and wrong types, and converting YAML scalars to their Go type
+func normalizeSpecValue(field string, value any, t reflect.Type) (any, error) {
+    if value == nil {
+        return nil, nil
+    }
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    switch t.Kind() {
+    case reflect.Struct:
+        m, ok := value.(map[string]any)
+        if !ok {
+            return nil, &specError{field: field, msg: "expected a mapping"}
+        }
+        fields := map[string]reflect.StructField{}
+        for i := 0; i < t.NumField(); i++ {
+            tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+            fields[tag] = t.Field(i)
+        }
+        result := map[string]any{}
+        for _, key := range sortedKeys(m) {
+            sf, ok := fields[key]
+            if !ok {
+                return nil, &specError{field: joinField(field, key), msg: "unknown field"}
+            }
+            v, err := normalizeSpecValue(joinField(field, key), m[key], sf.Type)
+            if err != nil {
+                return nil, err
+            }
+            result[key] = v
+        }
+        return result, nil
+
+    case reflect.Map:
+        m, ok := value.(map[string]any)
+        if !ok {
+            return nil, &specError{field: field, msg: "expected a mapping"}
+        }
+        result := map[string]any{}
+        for _, key := range sortedKeys(m) {
+            v, err := normalizeSpecValue(joinField(field, key), m[key], t.Elem())
+            if err != nil {
+                return nil, err
+            }
+            result[key] = v
+        }
+        return result, nil
+
+    case reflect.Slice:
+        items, ok := value.([]any)
+        if !ok {
+            return nil, &specError{field: field, msg: "expected a list"}
+        }
+        result := make([]any, len(items))
+        for i, item := range items {
+            v, err := normalizeSpecValue(fmt.Sprintf("%s[%d]", field, i), item, t.Elem())
+            if err != nil {
+                return nil, err
+            }
+            result[i] = v
+        }
+        return result, nil
+
+    case reflect.String:
+        switch v := value.(type) {
+        case string:
+            return v, nil
+        case yamlScalar:
+            return v.raw, nil
+        case float64:
+            return strconv.FormatFloat(v, 'f', -1, 64), nil
+        }
+        return nil, &specError{field: field, msg: "expected a string"}
+
+    case reflect.Bool:
+        switch v := value.(type) {
+        case bool:
+            return v, nil
+        case yamlScalar:
+            if !v.quoted {
+                switch strings.ToLower(v.raw) {
+                case "true", "yes", "on", "y":
+                    return true, nil
+                case "false", "no", "off", "n":
+                    return false, nil
+                }
+            }
+        }
+        return nil, &specError{field: field, msg: "expected true or false"}
+
+    case reflect.Int:
+        switch v := value.(type) {
+        case float64:
+            if v == float64(int(v)) {
+                return int(v), nil
+            }
+        case yamlScalar:
+            if n, err := strconv.Atoi(v.raw); err == nil && !v.quoted {
+                return n, nil
+            }
+        }
+        return nil, &specError{field: field, msg: "expected a whole number"}
+    }
+    return nil, &specError{field: field, msg: fmt.Sprintf("unsupported type %s", t)}
+}
+
+func joinField(parent, key string) string {
+    if parent == "" {
+        return key
+    }
+    return parent + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+    keys := make([]string, 0, len(m))
+    for key := range m {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// Check values and combinations that the wizard would never produce
+func (s *projectSpec) validate() error {
+    fail := func(field, format string, args ...any) error {
+        return &specError{field: field, msg: fmt.Sprintf(format, args...)}
+    }
+    oneOf := func(field, value string, allowed []string) error {
+        if value != "" && !contains(allowed, value) {
+            return fail(field, "must be one of %s (got %q)", strings.Join(allowed, ", "), value)
+        }
+        return nil
+    }
+
+    // A backend without a framework gets the wizard's default
+    if s.Backend != nil && s.Backend.Framework == "" {
+        s.Backend.Framework = "express"
+    }
+    if strings.TrimSpace(s.Name) == "" {
+        return fail("name", "is required")
+    }
+    if strings.ContainsAny(s.Name, `/\`) {
+        return fail("name", "must be a plain directory name (got %q)", s.Name)
+    }
+    if s.Layout == "" {
+        return fail("layout", "is required")
+    }
+    if err := oneOf("layout", s.Layout, mapKeys(layoutChoices)); err != nil {
+        return err
+    }
+
+    if err := oneOf("packageManager", s.PackageManager, packageManagerNames()); err != nil {
+        return err
+    }
+    if s.Bun != nil && *s.Bun && s.PackageManager != "" && s.PackageManager != "bun" {
+        return fail("bun", "is true but packageManager is %q", s.PackageManager)
+    }
+
+    hasFrontend := s.Layout == "frontend" || s.Layout == "fullstack"
+    hasBackend := s.Layout == "backend" || s.Layout == "fullstack"
+
+    if err := oneOf("monorepo", s.Monorepo, mapKeys(monorepoChoices)); err != nil {
+        return err
+    }
+    if s.Monorepo != "" && s.Monorepo != "none" {
+        if s.Layout != "fullstack" {
+            return fail("monorepo", "needs layout fullstack (got %q)", s.Layout)
+        }
+        if s.Frontend != nil && s.Frontend.Framework == "skip" {
+            return fail("monorepo", "needs a frontend but frontend.framework is \"skip\"")
+        }
+        if s.Backend != nil && !contains([]string{"express", "fastify", "hono", "nestjs"}, s.Backend.Framework) {
+            return fail("monorepo", "needs a TypeScript backend, not %s", s.Backend.Framework)
+        }
+    }
+    if s.Shared != nil && *s.Shared && (s.Monorepo == "" || s.Monorepo == "none") {
+        return fail("shared", "needs monorepo workspaces, turbo or nx")
+    }
+    if err := oneOf("ci", s.CI, mapKeys(ciChoices)); err != nil {
+        return err
+    }
+    if s.DockerCompose != nil && *s.DockerCompose && s.Docker != nil && !*s.Docker {
+        return fail("dockerCompose", "needs docker")
+    }
+
+    if s.Frontend != nil {
+        if !hasFrontend {
+            return fail("frontend", "is set but layout %q has no frontend", s.Layout)
+        }
+        if err := oneOf("frontend.framework", s.Frontend.Framework, mapKeys(frontendChoices)); err != nil {
+            return err
+        }
+        if s.Frontend.Framework == "skip" && (s.Frontend.Tailwind != nil || s.Frontend.Storybook != nil) {
+            return fail("frontend.framework", "is \"skip\" but tailwind or storybook is set")
+        }
+        if s.Frontend.Framework == "solid" && s.Frontend.Storybook != nil && *s.Frontend.Storybook {
+            return fail("frontend.storybook", "is not supported for solid")
+        }
+    }
+    if s.Backend != nil {
+        if !hasBackend {
+            return fail("backend", "is set but layout %q has no backend", s.Layout)
+        }
+        if err := oneOf("backend.framework", s.Backend.Framework, mapKeys(backendChoices)); err != nil {
+            return err
+        }
+        if (s.Backend.Framework == "go" || s.Backend.Framework == "fastapi") && s.ORM != "" && s.ORM != "none" {
+            return fail("orm", "%s needs a TypeScript backend, not %s", s.ORM, s.Backend.Framework)
+        }
+        if (s.Backend.Framework == "go" || s.Backend.Framework == "fastapi") && len(s.Models) > 0 {
+            return fail("models", "needs a TypeScript backend, not %s", s.Backend.Framework)
+        }
+    }
+    if err := oneOf("database", s.Database, mapKeys(dbChoices)); err != nil {
+        return err
+    }
+    if err := oneOf("orm", s.ORM, mapKeys(ormChoices)); err != nil {
+        return err
+    }
+    if s.Database == "mongodb" && s.ORM != "" && s.ORM != "prisma" && s.ORM != "none" {
+        return fail("orm", "%s does not support mongodb (use prisma)", s.ORM)
+    }
+    if !hasBackend {
+        // In spec order, so the first offending field is always the one reported
+        fields := []struct {
+            name string
+            set  bool
+        }{
+            {"database", s.Database != ""},
+            {"orm", s.ORM != ""},
+            {"models", s.Models != nil},
+        }
+        for _, field := range fields {
+            if field.set {
+                return fail(field.name, "needs a backend but layout is %q", s.Layout)
+            }
+        }
+    }
+    if len(s.Models) > 0 {
+        if s.ORM != "" && s.ORM != "prisma" {
+            return fail("models", "needs orm prisma (got %q)", s.ORM)
+        }
+        models, err := prismaModelsFromSpec(s.Models)
+        if err != nil {
+            return err
+        }
+        for _, engine := range databaseEngines {
+            if engine.name == s.Database {
+                if err := checkPrismaProvider(models, engine.prismaProvider); err != nil {
+                    return fail("models", "%v", err)
+                }
+            }
+        }
+    }
+    for _, key := range mapKeys(s.Env) {
+        if !isValidEnvKey(key) {
+            return fail("env."+key, "is not a valid environment variable name")
+        }
+        // VITE_* values go to the frontend, the rest to the backend
+        if strings.HasPrefix(key, "VITE_") && !hasFrontend {
+            return fail("env."+key, "is a frontend variable but layout %q has no frontend", s.Layout)
+        }
+        if !strings.HasPrefix(key, "VITE_") && !hasBackend {
+            return fail("env."+key, "needs a backend but layout is %q", s.Layout)
+        }
+    }
+    if s.Remote != "" && s.Remote != "skip" {
+        if !isValidURL(s.Remote) {
+            return fail("remote", "must be a URL or \"skip\" (got %q)", s.Remote)
+        }
+        if s.Git != nil && !*s.Git {
+            return fail("remote", "is set but git is false")
+        }
+    }
+    return nil
+}
+
+// Fill in every option the spec answers; flags given on the command line win.
+// Anything the spec leaves out takes the wizard's default
+func applySpec(s *projectSpec) {
+    setString := func(dst *string, value string) {
+        if *dst == "" {
+            *dst = value
+        }
+    }
+    setBool := func(dst *optionalBool, value *bool) {
+        if !dst.set && value != nil {
+            *dst = optionalBool{set: true, value: *value}
+        }
+    }
+
+    setString(&opts.name, s.Name)
+    setString(&opts.layout, s.Layout)
+    setString(&opts.pm, s.PackageManager)
+    if s.Bun != nil && *s.Bun {
+        setString(&opts.pm, "bun")
+    }
+    setString(&opts.monorepo, s.Monorepo)
+    setBool(&opts.shared, s.Shared)
+    setBool(&opts.git, s.Git)
+    if s.Frontend != nil {
+        setString(&opts.frontend, s.Frontend.Framework)
+        setBool(&opts.tailwind, s.Frontend.Tailwind)
+        setBool(&opts.storybook, s.Frontend.Storybook)
+    }
+    if s.Backend != nil {
+        setString(&opts.backend, s.Backend.Framework)
+    }
+    setString(&opts.db, s.Database)
+    setString(&opts.orm, s.ORM)
+    if len(s.Models) > 0 && opts.models == nil {
+        setString(&opts.orm, "prisma")
+        opts.models, _ = prismaModelsFromSpec(s.Models)
+    }
+    if s.Env != nil {
+        create := true
+        opts.envValues = s.Env
+        setBool(&opts.env, &create)
+    }
+    if s.Remote == "" {
+        setString(&opts.remote, "skip")
+    } else {
+        setString(&opts.remote, s.Remote)
+    }
+    setString(&opts.ci, s.CI)
+    setBool(&opts.docker, s.Docker)
+    setBool(&opts.dockerCompose, s.DockerCompose)
+    opts.yes = true
+}
+
+// Build the models a spec lists, pointing errors at the exact entry
+func prismaModelsFromSpec(specs []modelSpec) ([]prismaModel, error) {
+    models := make([]prismaModel, 0, len(specs))
+    for i, ms := range specs {
+        field := fmt.Sprintf("models[%d]", i)
+        if err := checkPrismaModelName(ms.Name, models); err != nil {
+            return nil, &specError{field: field + ".name", msg: err.Error()}
+        }
+        model := prismaModel{name: ms.Name}
+        for j, line := range ms.Fields {
+            f, err := parsePrismaField(line)
+            if err != nil {
+                return nil, &specError{field: fmt.Sprintf("%s.fields[%d]", field, j), msg: err.Error()}
+            }
+            model.fields = append(model.fields, f)
+        }
+        models = append(models, model)
+    }
+    resolved, err := resolvePrismaModels(models)
+    if err != nil {
+        return nil, &specError{field: "models", msg: err.Error()}
+    }
+    return resolved, nil
+}
+
+func isValidEnvKey(key string) bool {
+    if key == "" || (key[0] >= '0' && key[0] <= '9') {
+        return false
+    }
+    for _, c := range key {
+        if !(c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')) {
+            return false
+        }
+    }
+    return true
+}
+
+func mapKeys(m map[string]string) []string {
+    keys := make([]string, 0, len(m))
+    for key := range m {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
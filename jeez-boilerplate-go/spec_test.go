@@ -0,0 +1,117 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// Write a spec to a temporary file and load it
+func loadTestSpec(t *testing.T, file string, content string) (*projectSpec, error) {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), file)
+    if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+    return loadSpec(path)
+}
+
+func TestLoadSpec(t *testing.T) {
+    yaml := `name: my-app
+layout: fullstack
+git: yes
+frontend:
+  framework: vite
+  tailwind: false
+backend:
+  framework: express
+database: postgres
+models:
+  - name: Post
+    fields: [title String]
+env:
+  VITE_TITLE: "My App"
+  PORT: 3000
+`
+    json := `{"name": "my-app", "layout": "fullstack", "git": true,
+  "frontend": {"framework": "vite", "tailwind": false},
+  "backend": {"framework": "express"}, "database": "postgres",
+  "models": [{"name": "Post", "fields": ["title String"]}],
+  "env": {"VITE_TITLE": "My App", "PORT": "3000"}}`
+    for file, content := range map[string]string{"jeez.yaml": yaml, "jeez.json": json} {
+        t.Run(file, func(t *testing.T) {
+            spec, err := loadTestSpec(t, file, content)
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if spec.Name != "my-app" || spec.Layout != "fullstack" || spec.Database != "postgres" {
+                t.Errorf("got name %q, layout %q, database %q", spec.Name, spec.Layout, spec.Database)
+            }
+            if spec.Git == nil || !*spec.Git {
+                t.Errorf("git should be true")
+            }
+            if spec.Frontend == nil || spec.Frontend.Framework != "vite" || spec.Frontend.Tailwind == nil || *spec.Frontend.Tailwind {
+                t.Errorf("got frontend %+v", spec.Frontend)
+            }
+            if len(spec.Models) != 1 || spec.Models[0].Name != "Post" || len(spec.Models[0].Fields) != 1 {
+                t.Errorf("got models %+v", spec.Models)
+            }
+            if spec.Env["PORT"] != "3000" || spec.Env["VITE_TITLE"] != "My App" {
+                t.Errorf("got env %v", spec.Env)
+            }
+        })
+    }
+}
+
+func TestLoadSpecBackendFramework(t *testing.T) {
+    tests := []struct {
+        name    string
+        content string
+        want    string
+    }{
+        {"empty backend defaults to express", "name: a\nlayout: fullstack\nmonorepo: workspaces\nbackend: {}\n", "express"},
+        {"framework is kept", "name: a\nlayout: fullstack\nmonorepo: turbo\nbackend: {framework: hono}\n", "hono"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            spec, err := loadTestSpec(t, "jeez.yaml", tt.content)
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if spec.Backend.Framework != tt.want {
+                t.Errorf("got framework %q, want %q", spec.Backend.Framework, tt.want)
+            }
+        })
+    }
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+    tests := []struct {
+        name    string
+        content string
+        want    string
+    }{
+        {"syntax error", "name: a\n  layout: fullstack\n", "line 2: unexpected indentation"},
+        {"missing name", "layout: fullstack\n", "name: is required"},
+        {"unknown field", "name: a\nframework: react\n", "framework: unknown field"},
+        {"unknown nested field", "name: a\nlayout: frontend\nfrontend:\n  tailwnd: true\n", "frontend.tailwnd: unknown field"},
+        {"quoted boolean", "name: a\ngit: \"true\"\n", "git: expected true or false"},
+        {"list for a mapping", "name: a\nlayout: backend\nenv: [PORT]\n", "env: expected a mapping"},
+        {"scalar for a list", "name: a\nlayout: backend\nmodels: Post\n", "models: expected a list"},
+        {"bad choice", "name: a\nlayout: fullstack\ndatabase: oracle\n", `database: must be one of`},
+        {"model field", "name: a\nlayout: backend\nmodels:\n  - name: Post\n    fields: [title String, Title String]\n", "models[0].fields[1]"},
+        {"list entry type", "name: a\nlayout: backend\nmodels:\n  - name: Post\n    fields: [[title]]\n", "models[0].fields[0]: expected a string"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := loadTestSpec(t, "jeez.yaml", tt.content)
+            if err == nil {
+                t.Fatalf("expected an error containing %q", tt.want)
+            }
+            if !strings.Contains(err.Error(), "jeez.yaml: ") || !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("got error %q, want it to name the file and contain %q", err, tt.want)
+            }
+        })
+    }
+}
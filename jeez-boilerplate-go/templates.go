@@ -0,0 +1,180 @@
+package main
+
+import (
+    "bytes"
+    "embed"
+    "fmt"
+    "io/fs"
+    "path"
+    "strings"
+    "text/template"
+)
+
+// Generated files live under templates/<set>/, laid out as they appear in the
+// project. Paths and the contents of *.tmpl files are rendered with
+// text/template; a path segment that renders empty drops the file
+//
+//go:embed all:templates
+var templateFS embed.FS
+
+// templateData is what templates can refer to
+type templateData struct {
+    Name        string
+    Slug        string
+    DBName      string
+    DBPort      int
+    BackendPort int
+    // FrontendPort is the Vite dev server's port
+    FrontendPort int
+    FrontendDir  string
+    BackendDir   string
+    // Framework is the Vite template family: react, vue, svelte, solid or vanilla
+    Framework string
+    // Database is the engine the database recipe set up, or empty when it did not run;
+    // SQL is true for the engines a database/sql or SQLAlchemy driver opens
+    Database string
+    SQL      bool
+    // PrismaProvider is the schema.prisma datasource provider for that engine (postgresql by default)
+    PrismaProvider string
+    // ORM is prisma, drizzle, typeorm or kysely when the orm recipe runs; ESM is true
+    // when the backend runs as ES modules, so relative imports need a .js extension
+    ORM string
+    ESM bool
+    // UserModel is true when the ORM defines the User model that src/db.ts lists at /users;
+    // PrismaModels holds the model blocks of schema.prisma
+    UserModel    bool
+    PrismaModels string
+    // SharedPackage is the name of packages/shared; ZodSchemas holds a schema per ORM model
+    SharedPackage string
+    ZodSchemas    string
+    // PackageManager installs the Node apps; InstallCommand installs from the lockfile
+    // and PruneCommand then drops the dev dependencies
+    PackageManager string
+    Lockfile       string
+    InstallCommand string
+    PruneCommand   string
+    // BackendLanguage is typescript, go or python; BackendEntry is the compiled file
+    // a TypeScript backend starts from
+    BackendLanguage string
+    BackendEntry    string
+    // Features holds every recipe selected for this run, by name
+    Features map[string]bool
+}
+
+func (p *project) templateData() templateData {
+    // List every recipe so templates can test features that were not selected
+    features := map[string]bool{}
+    for _, r := range recipes {
+        features[r.Name()] = p.selected[r.Name()]
+    }
+    database := ""
+    if p.selected["database"] {
+        database = p.database.name
+    }
+    orm := ""
+    if p.selected["orm"] {
+        orm = p.orm.name
+    }
+    userModel := orm != ""
+    models := defaultPrismaModels()
+    if orm == "prisma" {
+        userModel = hasPrismaModel(p.models, "User")
+        models = p.models
+    }
+    zodSchemas := ""
+    if orm != "" {
+        zodSchemas = renderZodSchemas(models)
+    }
+    return templateData{
+        Name:            p.name,
+        Slug:            projectSlug(p.name),
+        DBName:          databaseName(p.name),
+        DBPort:          p.dbPort,
+        BackendPort:     p.backendPort,
+        FrontendPort:    p.frontendPort,
+        FrontendDir:     "frontend",
+        BackendDir:      "backend",
+        Framework:       p.framework.name,
+        Database:        database,
+        SQL:             database != "" && database != "mongodb",
+        PrismaProvider:  p.database.prismaProvider,
+        ORM:             orm,
+        ESM:             p.server.esm,
+        UserModel:       userModel,
+        PrismaModels:    renderPrismaModels(p.models, p.database.prismaProvider),
+        SharedPackage:   p.sharedPackageName(),
+        ZodSchemas:      zodSchemas,
+        PackageManager:  p.pm.name,
+        Lockfile:        p.pm.lockfile,
+        InstallCommand:  p.pm.ciInstallCommand(p.pmVersion),
+        PruneCommand:    p.pm.pruneCommand(p.pmVersion),
+        BackendLanguage: p.server.language,
+        BackendEntry:    p.server.entry,
+        Features:        features,
+    }
+}
+
+// Render the template set into the project
+func renderTemplates(set string, data templateData) error {
+    root := path.Join("templates", set)
+    return fs.WalkDir(templateFS, root, func(name string, d fs.DirEntry, err error) error {
+        if err != nil {
+            return fmt.Errorf("%sunknown template set %s: %w%s", ColorRed, set, err, ColorReset)
+        }
+        if d.IsDir() {
+            return nil
+        }
+
+        rel := strings.TrimPrefix(name, root+"/")
+        target, err := renderTemplateString(rel, rel, data)
+        if err != nil {
+            return err
+        }
+        if !isCompletePath(target) {
+            return nil
+        }
+
+        content, err := templateFS.ReadFile(name)
+        if err != nil {
+            return err
+        }
+        if strings.HasSuffix(target, ".tmpl") {
+            target = strings.TrimSuffix(target, ".tmpl")
+            rendered, err := renderTemplateString(rel, string(content), data)
+            if err != nil {
+                return err
+            }
+            content = []byte(rendered)
+        }
+
+        if err := ensureDirectory(path.Dir(target)); err != nil {
+            return err
+        }
+        if err := writeFile(target, content, 0644); err != nil {
+            return fmt.Errorf("%sfailed to write %s: %w%s", ColorRed, target, err, ColorReset)
+        }
+        return nil
+    })
+}
+
+func renderTemplateString(name string, text string, data templateData) (string, error) {
+    tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+    if err != nil {
+        return "", fmt.Errorf("%sbad template %s: %w%s", ColorRed, name, err, ColorReset)
+    }
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, data); err != nil {
+        return "", fmt.Errorf("%sfailed to render %s: %w%s", ColorRed, name, err, ColorReset)
+    }
+    return buf.String(), nil
+}
+
+// A rendered path with an empty segment was switched off by a condition
+func isCompletePath(p string) bool {
+    for _, segment := range strings.Split(p, "/") {
+        if segment == "" {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,11 @@
+// Table types for Kysely; keep them in step with src/migrations
+export interface UserTable {
+    id: string;
+    email: string;
+    firstName: string;
+    lastName: string;
+}
+
+export interface Database {
+    User: UserTable;
+}
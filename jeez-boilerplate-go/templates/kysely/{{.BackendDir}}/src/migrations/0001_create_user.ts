@@ -0,0 +1,15 @@
+import { Kysely } from 'kysely';
+
+export async function up(db: Kysely<any>): Promise<void> {
+    await db.schema
+        .createTable('User')
+        .addColumn('id', 'varchar(36)', (col) => col.primaryKey())
+        .addColumn('email', 'varchar(255)', (col) => col.notNull().unique())
+        .addColumn('firstName', 'varchar(255)', (col) => col.notNull())
+        .addColumn('lastName', 'varchar(255)', (col) => col.notNull())
+        .execute();
+}
+
+export async function down(db: Kysely<any>): Promise<void> {
+    await db.schema.dropTable('User').execute();
+}
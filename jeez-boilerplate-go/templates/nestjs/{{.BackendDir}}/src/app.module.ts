@@ -0,0 +1,13 @@
+import { Module } from '@nestjs/common';
+import { ConfigModule } from '@nestjs/config';
+import { AppController } from './app.controller';
+
+@Module({
+    imports: [
+        // .env holds the generated settings and .env.local this machine's overrides; under
+        // NODE_ENV=test, .env.test replaces .env.local. The first file to set a variable wins
+        ConfigModule.forRoot({ isGlobal: true, envFilePath: process.env.NODE_ENV === 'test' ? ['.env.test', '.env'] : ['.env.local', '.env'] }),
+    ],
+    controllers: [AppController],
+})
+export class AppModule {}
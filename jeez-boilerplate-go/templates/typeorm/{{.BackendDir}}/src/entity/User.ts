@@ -0,0 +1,17 @@
+import { Column, Entity, PrimaryGeneratedColumn } from 'typeorm';
+
+// Column types are spelled out because tsx does not emit decorator metadata
+@Entity('User')
+export class User {
+    @PrimaryGeneratedColumn('uuid')
+    id: string;
+
+    @Column('varchar', { unique: true })
+    email: string;
+
+    @Column('varchar')
+    firstName: string;
+
+    @Column('varchar')
+    lastName: string;
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path"
+    "strconv"
+    "strings"
+)
+
+// The apps a workspace links, as directories and package names
+var workspaceApps = []string{"frontend", "backend"}
+
+// workspaceMember is one package of the workspace
+type workspaceMember struct {
+    dir  string
+    name string
+    // library marks a package the apps import compiled, so it is built before they start
+    library bool
+}
+
+// Every package of the workspace, libraries first
+func (p *project) workspaceMembers() []workspaceMember {
+    var members []workspaceMember
+    if p.selected["shared"] {
+        members = append(members, workspaceMember{dir: sharedDir, name: p.sharedPackageName(), library: true})
+    }
+    for _, app := range workspaceApps {
+        members = append(members, workspaceMember{dir: app, name: app})
+    }
+    return members
+}
+
+// monorepoTool is what the workspace root runs the apps' dev and build scripts with
+type monorepoTool struct {
+    name  string
+    label string
+    // devDependency is installed at the root; templates/<name> holds its config, if any
+    devDependency string
+    // gitignore lists the tool's cache directories
+    gitignore []string
+}
+
+// In the same order as monorepoChoices
+var monorepoTools = []monorepoTool{
+    {name: "workspaces", label: "Workspaces only (concurrently runs both apps)", devDependency: "concurrently"},
+    {name: "turbo", label: "Workspaces with Turborepo", devDependency: "turbo", gitignore: []string{".turbo"}},
+    {name: "nx", label: "Workspaces with Nx", devDependency: "nx", gitignore: []string{".nx/cache", ".nx/workspace-data"}},
+}
+
+// Root scripts that run every package's dev script at once (dev) and build them
+// all (build). Turborepo and Nx build the libraries first from their config
+func (t monorepoTool) scripts(pm packageManager, members []workspaceMember) [][2]string {
+    switch t.name {
+    case "turbo":
+        return [][2]string{{"dev", "turbo run dev"}, {"build", "turbo run build"}}
+    case "nx":
+        return [][2]string{{"dev", "nx run-many -t dev"}, {"build", "nx run-many -t build"}}
+    }
+    // Counted from the end, so the apps are blue and green with or without a library
+    colors := []string{"magenta", "blue", "green"}[3-len(members):]
+    var names, commands, libraries, build []string
+    for _, m := range members {
+        names = append(names, path.Base(m.dir))
+        commands = append(commands, strconv.Quote(pm.workspaceRunCommand(m.name, "dev")))
+        build = append(build, pm.workspaceRunCommand(m.name, "build"))
+        if m.library {
+            libraries = append(libraries, pm.workspaceRunCommand(m.name, "build"))
+        }
+    }
+    dev := fmt.Sprintf("concurrently -n %s -c %s %s", strings.Join(names, ","), strings.Join(colors, ","), strings.Join(commands, " "))
+    return [][2]string{
+        {"dev", strings.Join(append(libraries, dev), " && ")},
+        {"build", strings.Join(build, " && ")},
+    }
+}
+
+// workspaceRecipe turns the project root into a workspace holding both apps,
+// so one install at the root sets everything up
+type workspaceRecipe struct{}
+
+func (r *workspaceRecipe) Name() string           { return "workspace" }
+func (r *workspaceRecipe) Description() string    { return "Link both apps in a workspace at the root" }
+func (r *workspaceRecipe) Dependencies() []string { return []string{"vite", "backend"} }
+
+func (r *workspaceRecipe) RequiredTools() []string {
+    if !flagsAllowFrontend() || !flagsAllowNodeBackend() || opts.monorepo == "none" {
+        return nil
+    }
+    return packageManagerFor(opts.pm).tools()
+}
+
+func (r *workspaceRecipe) Prompt(p *project) (bool, error) {
+    if p.server.language != "typescript" {
+        if opts.monorepo != "" && opts.monorepo != "none" {
+            return false, fmt.Errorf("%sa workspace needs a TypeScript backend, not %s%s", ColorRed, p.server.label, ColorReset)
+        }
+        return false, nil
+    }
+    items := make([]string, 0, len(monorepoTools)+1)
+    for _, tool := range monorepoTools {
+        items = append(items, tool.label)
+    }
+    items = append(items, "No workspace")
+
+    choice, answered := presetChoice(opts.monorepo, "none", monorepoChoices)
+    n, _ := strconv.Atoi(chooseFromMenu("Select your "+p.pm.name+" workspace setup:", items, choice, answered))
+    if n > len(monorepoTools) {
+        fmt.Printf("%sSkipping workspace setup.%s\n", ColorYellow, ColorReset)
+        return false, nil
+    }
+    p.monorepo = monorepoTools[n-1]
+    return true, nil
+}
+
+func (r *workspaceRecipe) Apply(p *project) error {
+    tool := p.monorepo
+    fmt.Printf("%sSetting up the %s workspace...%s\n", ColorBlue, p.pm.name, ColorReset)
+
+    // The root scripts address each app by its package name, and Turborepo and Nx
+    // run a task by script name, so every app needs a dev script
+    for _, app := range workspaceApps {
+        if err := editPackageJSON(app, func(pkg *packageJSON) error {
+            pkg.setField("name", app)
+            if _, ok := pkg.root.object("scripts").get("dev"); !ok {
+                if start, ok := pkg.root.object("scripts").get("start"); ok {
+                    pkg.setScript("dev", fmt.Sprint(start))
+                }
+            }
+            return nil
+        }); err != nil {
+            return err
+        }
+    }
+
+    if err := writeFile("package.json", []byte(fmt.Sprintf("{\n  \"name\": %s,\n  \"private\": true\n}\n", strconv.Quote(projectSlug(p.name)))), 0644); err != nil {
+        return fmt.Errorf("%sfailed to write package.json: %w%s", ColorRed, err, ColorReset)
+    }
+    members := p.workspaceMembers()
+    var dirs []string
+    for _, m := range members {
+        dirs = append(dirs, m.dir)
+    }
+    if err := editPackageJSON(".", func(pkg *packageJSON) error {
+        // pnpm reads its workspaces from pnpm-workspace.yaml instead
+        if p.pm.name != "pnpm" {
+            pkg.setWorkspaces(dirs)
+        }
+        for _, script := range tool.scripts(p.pm, members) {
+            pkg.setScript(script[0], script[1])
+        }
+        p.setPackageManagerField(pkg)
+        return nil
+    }); err != nil {
+        return err
+    }
+    if p.pm.name == "pnpm" {
+        if err := writeFile("pnpm-workspace.yaml", []byte("packages:\n  - "+strings.Join(dirs, "\n  - ")+"\n"), 0644); err != nil {
+            return fmt.Errorf("%sfailed to write pnpm-workspace.yaml: %w%s", ColorRed, err, ColorReset)
+        }
+    }
+    if tool.name != "workspaces" {
+        if err := renderTemplates(tool.name, p.templateData()); err != nil {
+            return err
+        }
+    }
+
+    // The root install replaces each app's own lockfile and node_modules
+    for _, app := range workspaceApps {
+        for _, name := range []string{p.pm.lockfile, "node_modules"} {
+            if err := removeProjectPath(app + "/" + name); err != nil {
+                return err
+            }
+        }
+    }
+    if err := runPackageManager(".", p.pm.addRootDevCommand(tool.devDependency)); err != nil {
+        return fmt.Errorf("%sfailed to install %s: %w%s", ColorRed, tool.devDependency, err, ColorReset)
+    }
+    if err := p.ensureLockfile("."); err != nil {
+        return err
+    }
+
+    if err := addToGitignore("Installed packages and build caches", append([]string{"node_modules"}, tool.gitignore...)); err != nil {
+        return fmt.Errorf("%sfailed to update .gitignore: %w%s", ColorRed, err, ColorReset)
+    }
+    fmt.Printf("%sJeez! Workspace ready: run '%s run dev' at the root to start both apps.%s\n", ColorGreen, p.pm.name, ColorReset)
+    return nil
+}
+
+// Helper function to delete a project-relative file or directory if it exists
+func removeProjectPath(path string) error {
+    if !pathExistsQuiet(path) {
+        return nil
+    }
+    if opts.dryRun {
+        recordPlan("remove", path, "")
+        return nil
+    }
+    if err := os.RemoveAll(projectPath(path)); err != nil {
+        return fmt.Errorf("%sfailed to remove %s: %w%s", ColorRed, path, err, ColorReset)
+    }
+    return nil
+}
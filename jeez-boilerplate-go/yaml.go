@@ -0,0 +1,305 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// yamlScalar is an untyped YAML value; its meaning (string, bool, number)
+// is decided later by the field it is decoded into
+type yamlScalar struct {
+    raw    string
+    quoted bool
+}
+
+type yamlLine struct {
+    number  int
+    indent  int
+    content string
+}
+
+type yamlParser struct {
+    lines []yamlLine
+    pos   int
+}
+
+// Parse the block-style subset of YAML that spec files need: mappings,
+// sequences, flow lists, quoted and plain scalars and comments. Mappings
+// become map[string]any, sequences []any and scalars yamlScalar
+func parseYAML(data []byte) (any, error) {
+    p := &yamlParser{}
+    for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+        if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "...") {
+            continue
+        }
+        trimmed := strings.TrimLeft(line, " ")
+        if strings.HasPrefix(trimmed, "\t") {
+            return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+        }
+        content := strings.TrimSpace(stripYAMLComment(trimmed))
+        if content == "" {
+            continue
+        }
+        p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(line) - len(trimmed), content: content})
+    }
+    if len(p.lines) == 0 {
+        return map[string]any{}, nil
+    }
+    value, err := p.parseBlock(p.lines[0].indent)
+    if err != nil {
+        return nil, err
+    }
+    if p.pos < len(p.lines) {
+        return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].number)
+    }
+    return value, nil
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+    if isYAMLSequenceItem(p.lines[p.pos].content) {
+        return p.parseSequence(indent)
+    }
+    return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseMapping(indent int) (any, error) {
+    result := map[string]any{}
+    for p.pos < len(p.lines) {
+        line := p.lines[p.pos]
+        if line.indent < indent {
+            break
+        }
+        if line.indent > indent {
+            return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+        }
+        if isYAMLSequenceItem(line.content) {
+            return nil, fmt.Errorf("line %d: expected a key, found a list item", line.number)
+        }
+        key, rest, ok := splitYAMLKey(line.content)
+        if !ok {
+            return nil, fmt.Errorf("line %d: expected 'key: value'", line.number)
+        }
+        if _, exists := result[key]; exists {
+            return nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+        }
+        p.pos++
+
+        if rest != "" {
+            value, err := parseYAMLInline(rest, line.number)
+            if err != nil {
+                return nil, err
+            }
+            result[key] = value
+            continue
+        }
+
+        // The value is a nested block, a list at the same indent, or empty
+        if p.pos < len(p.lines) {
+            next := p.lines[p.pos]
+            if next.indent > indent || (next.indent == indent && isYAMLSequenceItem(next.content)) {
+                value, err := p.parseBlock(next.indent)
+                if err != nil {
+                    return nil, err
+                }
+                result[key] = value
+                continue
+            }
+        }
+        result[key] = nil
+    }
+    return result, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (any, error) {
+    result := []any{}
+    for p.pos < len(p.lines) {
+        line := p.lines[p.pos]
+        if line.indent < indent || !isYAMLSequenceItem(line.content) {
+            if line.indent > indent {
+                return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+            }
+            break
+        }
+        if line.indent > indent {
+            return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+        }
+
+        rest := strings.TrimLeft(line.content[1:], " ")
+        if rest == "" {
+            p.pos++
+            if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+                value, err := p.parseBlock(p.lines[p.pos].indent)
+                if err != nil {
+                    return nil, err
+                }
+                result = append(result, value)
+            } else {
+                result = append(result, nil)
+            }
+            continue
+        }
+
+        // "- key: value" starts a mapping whose keys line up with "key"
+        if _, _, ok := splitYAMLKey(rest); ok && !strings.HasPrefix(rest, "[") && !strings.HasPrefix(rest, "{") {
+            itemIndent := line.indent + len(line.content) - len(rest)
+            p.lines[p.pos] = yamlLine{number: line.number, indent: itemIndent, content: rest}
+            value, err := p.parseMapping(itemIndent)
+            if err != nil {
+                return nil, err
+            }
+            result = append(result, value)
+            continue
+        }
+
+        value, err := parseYAMLInline(rest, line.number)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, value)
+        p.pos++
+    }
+    return result, nil
+}
+
+func isYAMLSequenceItem(content string) bool {
+    return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// Split "key: value" on the first colon outside quotes
+func splitYAMLKey(content string) (string, string, bool) {
+    var quote byte
+    for i := 0; i < len(content); i++ {
+        c := content[i]
+        switch {
+        case quote != 0:
+            if c == quote {
+                quote = 0
+            }
+        case c == '"' || c == '\'':
+            if i == 0 {
+                quote = c
+            }
+        case c == ':' && (i == len(content)-1 || content[i+1] == ' '):
+            key := strings.TrimSpace(content[:i])
+            if key == "" {
+                return "", "", false
+            }
+            if unquoted, err := unquoteYAML(key); err == nil {
+                key = unquoted
+            }
+            return key, strings.TrimSpace(content[i+1:]), true
+        }
+    }
+    return "", "", false
+}
+
+func parseYAMLInline(s string, lineNumber int) (any, error) {
+    switch {
+    case strings.HasPrefix(s, "["):
+        if !strings.HasSuffix(s, "]") {
+            return nil, fmt.Errorf("line %d: unterminated flow list", lineNumber)
+        }
+        items := []any{}
+        for _, part := range splitYAMLFlow(s[1 : len(s)-1]) {
+            value, err := parseYAMLInline(part, lineNumber)
+            if err != nil {
+                return nil, err
+            }
+            items = append(items, value)
+        }
+        return items, nil
+    case strings.HasPrefix(s, "{"):
+        if !strings.HasSuffix(s, "}") {
+            return nil, fmt.Errorf("line %d: unterminated flow mapping", lineNumber)
+        }
+        result := map[string]any{}
+        for _, part := range splitYAMLFlow(s[1 : len(s)-1]) {
+            key, rest, ok := splitYAMLKey(part)
+            if !ok {
+                return nil, fmt.Errorf("line %d: expected 'key: value' in flow mapping", lineNumber)
+            }
+            value, err := parseYAMLInline(rest, lineNumber)
+            if err != nil {
+                return nil, err
+            }
+            result[key] = value
+        }
+        return result, nil
+    case strings.HasPrefix(s, "\"") || strings.HasPrefix(s, "'"):
+        value, err := unquoteYAML(s)
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+        }
+        return yamlScalar{raw: value, quoted: true}, nil
+    case s == "~" || s == "null":
+        return nil, nil
+    case s == "|" || s == ">":
+        return nil, fmt.Errorf("line %d: block scalars are not supported, use a quoted string", lineNumber)
+    }
+    return yamlScalar{raw: s}, nil
+}
+
+// Split the inside of a flow collection on commas outside quotes
+func splitYAMLFlow(s string) []string {
+    var parts []string
+    var quote byte
+    start := 0
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        switch {
+        case quote != 0:
+            if c == quote {
+                quote = 0
+            }
+        case (c == '"' || c == '\'') && startsYAMLQuote(s, i):
+            quote = c
+        case c == ',':
+            parts = append(parts, strings.TrimSpace(s[start:i]))
+            start = i + 1
+        }
+    }
+    if last := strings.TrimSpace(s[start:]); last != "" {
+        parts = append(parts, last)
+    }
+    return parts
+}
+
+func unquoteYAML(s string) (string, error) {
+    if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+        return strconv.Unquote(s)
+    }
+    if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+        return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+    }
+    return "", fmt.Errorf("malformed quoted string %s", s)
+}
+
+// A quote only opens a quoted scalar at the start of a value, so
+// apostrophes inside plain text are left alone
+func startsYAMLQuote(s string, i int) bool {
+    j := i - 1
+    for j >= 0 && s[j] == ' ' {
+        j--
+    }
+    return j < 0 || strings.IndexByte(":-[{,", s[j]) >= 0
+}
+
+// Drop a trailing "# comment" that is not inside quotes
+func stripYAMLComment(s string) string {
+    var quote byte
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        switch {
+        case quote != 0:
+            if c == quote {
+                quote = 0
+            }
+        case (c == '"' || c == '\'') && startsYAMLQuote(s, i):
+            quote = c
+        case c == '#' && (i == 0 || s[i-1] == ' '):
+            return s[:i]
+        }
+    }
+    return s
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestParseYAML(t *testing.T) {
+    plain := func(s string) yamlScalar { return yamlScalar{raw: s} }
+    quoted := func(s string) yamlScalar { return yamlScalar{raw: s, quoted: true} }
+    tests := []struct {
+        name  string
+        input string
+        want  any
+    }{
+        {
+            name:  "empty document",
+            input: "# nothing here\n",
+            want:  map[string]any{},
+        },
+        {
+            name:  "plain and quoted scalars",
+            input: "name: my-app\ngit: true\nport: 3000\nremote: \"https://example.com/a.git\"\nquote: 'it''s'\n",
+            want: map[string]any{
+                "name":   plain("my-app"),
+                "git":    plain("true"),
+                "port":   plain("3000"),
+                "remote": quoted("https://example.com/a.git"),
+                "quote":  quoted("it's"),
+            },
+        },
+        {
+            name:  "comments and nulls",
+            input: "name: my-app # the directory\nlabel: \"a # b\"\nci: ~\norm: null\n",
+            want: map[string]any{
+                "name":  plain("my-app"),
+                "label": quoted("a # b"),
+                "ci":    nil,
+                "orm":   nil,
+            },
+        },
+        {
+            name:  "nested mapping",
+            input: "frontend:\n  framework: react\n  tailwind: yes\n",
+            want: map[string]any{
+                "frontend": map[string]any{"framework": plain("react"), "tailwind": plain("yes")},
+            },
+        },
+        {
+            name:  "block list of scalars",
+            input: "fields:\n  - title String\n  - \"views Int\"\n",
+            want:  map[string]any{"fields": []any{plain("title String"), quoted("views Int")}},
+        },
+        {
+            name:  "list of mappings",
+            input: "models:\n  - name: Post\n    fields: [title String, author User]\n  - name: Tag\n",
+            want: map[string]any{
+                "models": []any{
+                    map[string]any{"name": plain("Post"), "fields": []any{plain("title String"), plain("author User")}},
+                    map[string]any{"name": plain("Tag")},
+                },
+            },
+        },
+        {
+            name:  "flow mapping",
+            input: "env: {API_KEY: abc, VITE_TITLE: 'Hello, world'}\n",
+            want:  map[string]any{"env": map[string]any{"API_KEY": plain("abc"), "VITE_TITLE": quoted("Hello, world")}},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseYAML([]byte(tt.input))
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("got %#v, want %#v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        want  string
+    }{
+        {"tab indentation", "frontend:\n\tframework: react\n", "line 2: tabs are not allowed for indentation"},
+        {"duplicate key", "name: a\ngit: true\nname: b\n", `line 3: duplicate key "name"`},
+        {"deeper line", "name: a\n  git: true\n", "line 2: unexpected indentation"},
+        {"missing colon", "name: a\ngit\n", "line 2: expected 'key: value'"},
+        {"list item in mapping", "frontend:\n  framework: react\n  - vue\n", "line 3: expected a key, found a list item"},
+        {"unterminated flow list", "fields: [a, b\n", "line 1: unterminated flow list"},
+        {"block scalar", "name: |\n  my-app\n", "line 1: block scalars are not supported"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := parseYAML([]byte(tt.input))
+            if err == nil {
+                t.Fatalf("expected an error containing %q", tt.want)
+            }
+            if !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("got error %q, want it to contain %q", err, tt.want)
+            }
+        })
+    }
+}